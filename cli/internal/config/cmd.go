@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// key is a settable config key. value points at a *string, *bool, *int, or
+// *[]string, and isPath expands ~ in string values.
+type key struct {
+	value  interface{}
+	isPath bool
+}
+
+var keys = map[string]key{
+	"repos_path":          {&REPOS_PATH, true},
+	"repos_glob":          {&REPOS_GLOB, true},
+	"cli_path":            {&CLI_PATH, true},
+	"dotfiles_repo":       {&DOTFILES_REPO, false},
+	"devspace_namespace":  {&DEVSPACE_NAMESPACE, false},
+	"github_username":     {&GITHUB_USERNAME, false},
+	"github_email":        {&GITHUB_EMAIL, false},
+	"repos":               {&REPOS, false},
+	"browser":             {&BROWSER, false},
+	"browser_open_delay":  {&BROWSER_OPEN_DELAY, false},
+	"ticket_pattern":      {&TICKET_PATTERN, false},
+	"ticket_format":       {&TICKET_FORMAT, false},
+	"jira_url":            {&JIRA_URL, false},
+	"clean_dirs":          {&CLEAN_DIRS, false},
+	"large_file_mb":       {&LARGE_FILE_MB, false},
+	"large_file_allow":    {&LARGE_FILE_ALLOW, false},
+	"notify":              {&NOTIFY, false},
+	"save_add_all":        {&SAVE_ADD_ALL, false},
+	"confirm_destructive": {&CONFIRM_DESTRUCTIVE, false},
+	"archive_after_days":  {&ARCHIVE_AFTER_DAYS, false},
+	"datadog_service":     {&DATADOG_SERVICE, false},
+	"datadog_env":         {&DATADOG_ENV, false},
+	"datadog_instance":    {&DATADOG_INSTANCE, false},
+}
+
+func lookup(name string) (key, error) {
+	if name == "dotfiles" {
+		return key{}, fmt.Errorf("dotfiles is a list of src and dest pairs. edit it in %v", CONFIG_PATH)
+	}
+	k, ok := keys[name]
+	if !ok {
+		names := []string{}
+		for n := range keys {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return key{}, fmt.Errorf("unknown config key %v. valid keys: %v", name, strings.Join(names, ", "))
+	}
+	return k, nil
+}
+
+// format prints a key's value the way Set accepts it. Lists are
+// comma-separated.
+func (k key) format() string {
+	switch v := k.value.(type) {
+	case *string:
+		return *v
+	case *[]string:
+		return strings.Join(*v, ",")
+	case *bool:
+		return strconv.FormatBool(*v)
+	case *int:
+		return strconv.Itoa(*v)
+	}
+	return ""
+}
+
+// parse converts value to the key's type, so it's written to the config
+// file as a YAML bool, int, or list rather than a string.
+func (k key) parse(name string, value string) (interface{}, error) {
+	switch k.value.(type) {
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%v must be true or false, not %v", name, value)
+		}
+		return b, nil
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%v must be a positive number, not %v", name, value)
+		}
+		return n, nil
+	case *[]string:
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	}
+	if k.isPath {
+		return ExpandHome(value), nil
+	}
+	return value, nil
+}
+
+func Get(params []string) error {
+	k, err := lookup(params[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(k.format())
+	return nil
+}
+
+// Set writes a key to the config file. Lists are given comma-separated and
+// replace the whole list.
+func Set(params []string) error {
+	name := params[0]
+	k, err := lookup(name)
+	if err != nil {
+		return err
+	}
+	value, err := k.parse(name, params[1])
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{}
+	bytes, err := os.ReadFile(CONFIG_PATH)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = yaml.Unmarshal(bytes, &values)
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	bytes, err = yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(CONFIG_PATH), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(CONFIG_PATH, bytes, 0644)
+}
+
+// Values returns every settable key with its resolved value, sorted by key.
+func Values() [][2]string {
+	names := []string{}
+	for n := range keys {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	values := [][2]string{}
+	for _, n := range names {
+		values = append(values, [2]string{n, keys[n].format()})
+	}
+	return values
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetTypedValues(t *testing.T) {
+	defer func(p string) { CONFIG_PATH = p }(CONFIG_PATH)
+	CONFIG_PATH = path.Join(t.TempDir(), "config.yaml")
+	for _, kv := range [][2]string{
+		{"save_add_all", "true"},
+		{"large_file_mb", "25"},
+		{"clean_dirs", "target, dist"},
+		{"jira_url", "https://org.atlassian.net"},
+	} {
+		if err := Set(kv[:]); err != nil {
+			t.Fatalf("set %v: %v", kv[0], err)
+		}
+	}
+	bytes, err := os.ReadFile(CONFIG_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := string(bytes)
+	for _, want := range []string{"save_add_all: true\n", "large_file_mb: 25\n", "clean_dirs:\n    - target\n    - dist\n"} {
+		if !strings.Contains(written, want) {
+			t.Errorf("config file %q doesn't contain %q", written, want)
+		}
+	}
+}
+
+func TestSetRejectsInvalidValues(t *testing.T) {
+	defer func(p string) { CONFIG_PATH = p }(CONFIG_PATH)
+	CONFIG_PATH = path.Join(t.TempDir(), "config.yaml")
+	for _, kv := range [][2]string{
+		{"notify", "sometimes"},
+		{"archive_after_days", "soon"},
+		{"large_file_mb", "-1"},
+		{"dotfiles", "x"},
+		{"nope", "x"},
+	} {
+		if err := Set(kv[:]); err == nil {
+			t.Errorf("set %v %v: expected an error", kv[0], kv[1])
+		}
+	}
+	if _, err := os.Stat(CONFIG_PATH); !os.IsNotExist(err) {
+		t.Error("invalid values were written to the config file")
+	}
+}
+
+func TestKeysFormat(t *testing.T) {
+	if got := keys["confirm_destructive"].format(); got != "false" && got != "true" {
+		t.Errorf("got %q", got)
+	}
+	defer func(d []string) { CLEAN_DIRS = d }(CLEAN_DIRS)
+	CLEAN_DIRS = []string{"a", "b"}
+	if got := keys["clean_dirs"].format(); got != "a,b" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestKeysCoverSchema(t *testing.T) {
+	fields := reflect.TypeOf(file{})
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Tag.Get("yaml")
+		if _, ok := keys[name]; !ok && name != "dotfiles" {
+			t.Errorf("config key %v can't be read or set with config get/set", name)
+		}
+	}
+}
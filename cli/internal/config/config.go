@@ -1,17 +1,241 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
 	"path"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
 )
 
 var home = os.Getenv("HOME")
 
-const DOTFILES_REPO = "dotfiles"
-const DEVSPACE_NAMESPACE = "dev-devonfulcher"
+const REPO_NAME = "toolbelt"
+
+var DOTFILES_REPO = "dotfiles"
+var DEVSPACE_NAMESPACE = "dev-devonfulcher"
+var GITHUB_USERNAME = "DevonFulcher"
+
+// GITHUB_EMAIL is the commit author `standup` looks for. Empty means git's
+// user.email.
+var GITHUB_EMAIL = ""
 
 var REPOS_PATH = path.Join(home, "git")
+
+// REPOS_GLOB, when set, replaces the immediate children of REPOS_PATH as
+// the set of repos, e.g. "~/git/*/*" for repos grouped by org.
+var REPOS_GLOB = ""
+
 var CLI_PATH = path.Join(home, "cli")
 var DOTFILES_PATH = path.Join(REPOS_PATH, DOTFILES_REPO)
 
+// CONFIRM_DESTRUCTIVE prompts before running commands marked destructive.
+// TOOLBELT_CONFIRM_DESTRUCTIVE overrides the config file.
+var CONFIRM_DESTRUCTIVE = false
+
+// BROWSER is a command template for opening URLs, see browser.Template.
+var BROWSER = ""
+
+// BROWSER_OPEN_DELAY is waited between opening several URLs at once, since
+// some browsers drop tabs opened in quick succession.
+var BROWSER_OPEN_DELAY = "500ms"
+
+// TICKET_PATTERN finds a ticket id in a branch name. The first capture
+// group, or the whole match if there is none, is the ticket. Empty disables
+// ticket prefixes.
+var TICKET_PATTERN = `^([A-Z][A-Z0-9]+-[0-9]+)`
+
+// JIRA_URL is the base URL of the Jira site, e.g. https://org.atlassian.net.
+var JIRA_URL = ""
+
+// TICKET_FORMAT is how `git save` prefixes commit messages with the ticket.
+var TICKET_FORMAT = "{ticket}: {message}"
+
+// CLEAN_DIRS are the build artifact directories `clean` removes.
+var CLEAN_DIRS = []string{"target", "node_modules", ".pytest_cache", "__pycache__", ".mypy_cache", ".ruff_cache"}
+
+// LARGE_FILE_MB is the size above which `git save` asks before committing a
+// staged file, unless the file matches a LARGE_FILE_ALLOW glob.
+var LARGE_FILE_MB = 10
+var LARGE_FILE_ALLOW = []string{}
+
+// ARCHIVE_AFTER_DAYS is how long a repo goes without a commit or fetch
+// before `repos archive` offers to move it into ARCHIVE_PATH.
+var ARCHIVE_AFTER_DAYS = 365
+var ARCHIVE_PATH = path.Join(REPOS_PATH, "archive")
+
+// DATADOG_SERVICE, DATADOG_ENV, and DATADOG_INSTANCE are the defaults for
+// `datadog errors`. Service and env may be comma-separated.
+var DATADOG_SERVICE = ""
+var DATADOG_ENV = ""
+var DATADOG_INSTANCE = "dbtlabsmt"
+
+// SAVE_ADD_ALL makes `git save` stage untracked files too, like it did
+// before it defaulted to tracked changes only.
+var SAVE_ADD_ALL = false
+
+// NOTIFY sends a desktop notification when long commands like `git save`
+// and `dev test` finish.
+var NOTIFY = false
+
+var STATE_PATH = path.Join(home, ".toolbelt")
+var CONFIG_PATH = path.Join(home, ".config", "toolbelt", "config.yaml")
+
 var VSCODE_DOTFILES_EXTENSIONS = path.Join(DOTFILES_PATH, "vscode/extensions.txt")
+var VSCODE_DOTFILES_SETTINGS = path.Join(DOTFILES_PATH, "vscode/settings.json")
+
+type Dotfile struct {
+	// Src is relative to DOTFILES_PATH.
+	Src  string `yaml:"src"`
+	Dest string `yaml:"dest"`
+}
+
+// DOTFILES are synced by `dot pull` and `dot push` in addition to the VS Code
+// settings and extensions.
+var DOTFILES = []Dotfile{}
+
+// REPOS are the GitHub repos, as "org/name", that `repos clone` clones into
+// REPOS_PATH.
+var REPOS = []string{}
+
+type file struct {
+	ReposPath          string    `yaml:"repos_path"`
+	ReposGlob          string    `yaml:"repos_glob"`
+	CliPath            string    `yaml:"cli_path"`
+	DotfilesRepo       string    `yaml:"dotfiles_repo"`
+	DevspaceNamespace  string    `yaml:"devspace_namespace"`
+	GithubUsername     string    `yaml:"github_username"`
+	GithubEmail        string    `yaml:"github_email"`
+	Dotfiles           []Dotfile `yaml:"dotfiles"`
+	Repos              []string  `yaml:"repos"`
+	TicketPattern      string    `yaml:"ticket_pattern"`
+	TicketFormat       string    `yaml:"ticket_format"`
+	JiraURL            string    `yaml:"jira_url"`
+	CleanDirs          []string  `yaml:"clean_dirs"`
+	LargeFileMB        int       `yaml:"large_file_mb"`
+	LargeFileAllow     []string  `yaml:"large_file_allow"`
+	Notify             bool      `yaml:"notify"`
+	SaveAddAll         bool      `yaml:"save_add_all"`
+	DatadogService     string    `yaml:"datadog_service"`
+	DatadogEnv         string    `yaml:"datadog_env"`
+	DatadogInstance    string    `yaml:"datadog_instance"`
+	ArchiveAfterDays   int       `yaml:"archive_after_days"`
+	ConfirmDestructive bool      `yaml:"confirm_destructive"`
+	Browser            string    `yaml:"browser"`
+	BrowserOpenDelay   string    `yaml:"browser_open_delay"`
+}
+
+// Load applies the values in CONFIG_PATH on top of the defaults above. A
+// missing config file is not an error.
+func Load() error {
+	err := loadFile()
+	if err != nil {
+		return err
+	}
+	if v, ok := os.LookupEnv("TOOLBELT_CONFIRM_DESTRUCTIVE"); ok {
+		CONFIRM_DESTRUCTIVE, _ = strconv.ParseBool(v)
+	}
+	return nil
+}
+
+// LoadFrom is Load with an explicit config file, which unlike the default
+// must exist.
+func LoadFrom(p string) error {
+	p = ExpandHome(p)
+	if _, err := os.Stat(p); err != nil {
+		return fmt.Errorf("config file %v doesn't exist", p)
+	}
+	CONFIG_PATH = p
+	return Load()
+}
+
+func loadFile() error {
+	bytes, err := os.ReadFile(CONFIG_PATH)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var f file
+	err = yaml.Unmarshal(bytes, &f)
+	if err != nil {
+		return err
+	}
+	override(&REPOS_PATH, ExpandHome(f.ReposPath))
+	override(&REPOS_GLOB, ExpandHome(f.ReposGlob))
+	override(&CLI_PATH, ExpandHome(f.CliPath))
+	override(&DOTFILES_REPO, f.DotfilesRepo)
+	override(&DEVSPACE_NAMESPACE, f.DevspaceNamespace)
+	override(&GITHUB_USERNAME, f.GithubUsername)
+	override(&GITHUB_EMAIL, f.GithubEmail)
+	override(&BROWSER, f.Browser)
+	override(&BROWSER_OPEN_DELAY, f.BrowserOpenDelay)
+	override(&TICKET_PATTERN, f.TicketPattern)
+	override(&TICKET_FORMAT, f.TicketFormat)
+	override(&JIRA_URL, f.JiraURL)
+	override(&DATADOG_SERVICE, f.DatadogService)
+	override(&DATADOG_ENV, f.DatadogEnv)
+	override(&DATADOG_INSTANCE, f.DatadogInstance)
+	CONFIRM_DESTRUCTIVE = f.ConfirmDestructive
+	NOTIFY = f.Notify
+	SAVE_ADD_ALL = f.SaveAddAll
+	for _, d := range f.Dotfiles {
+		DOTFILES = append(DOTFILES, Dotfile{Src: d.Src, Dest: ExpandHome(d.Dest)})
+	}
+	REPOS = append(REPOS, f.Repos...)
+	if len(f.CleanDirs) > 0 {
+		CLEAN_DIRS = f.CleanDirs
+	}
+	if f.LargeFileMB > 0 {
+		LARGE_FILE_MB = f.LargeFileMB
+	}
+	LARGE_FILE_ALLOW = append(LARGE_FILE_ALLOW, f.LargeFileAllow...)
+	if f.ArchiveAfterDays > 0 {
+		ARCHIVE_AFTER_DAYS = f.ArchiveAfterDays
+	}
+	ARCHIVE_PATH = path.Join(REPOS_PATH, "archive")
+	DOTFILES_PATH = path.Join(REPOS_PATH, DOTFILES_REPO)
+	VSCODE_DOTFILES_EXTENSIONS = path.Join(DOTFILES_PATH, "vscode/extensions.txt")
+	VSCODE_DOTFILES_SETTINGS = path.Join(DOTFILES_PATH, "vscode/settings.json")
+	return nil
+}
+
+// CheckFile parses CONFIG_PATH strictly, so misspelled or unknown keys,
+// which Load ignores, are reported.
+func CheckFile() error {
+	b, err := os.ReadFile(CONFIG_PATH)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	var f file
+	err = decoder.Decode(&f)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func override(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+func ExpandHome(p string) string {
+	if p == "~" {
+		return home
+	}
+	if len(p) > 1 && p[:2] == "~/" {
+		return path.Join(home, p[2:])
+	}
+	return p
+}
@@ -0,0 +1,48 @@
+package edit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/shell"
+)
+
+// target finds the source for a top-level command, falling back to the
+// command tree itself.
+func target(repoDir string, cmd string) string {
+	cliDir := path.Join(repoDir, "cli")
+	for _, dir := range []string{"pkg", "internal"} {
+		candidate := path.Join(cliDir, dir, cmd)
+		if fs.Exists(candidate) {
+			return candidate
+		}
+	}
+	return path.Join(cliDir, "internal", "tree", "tree.go")
+}
+
+func Run(params []string) error {
+	flags := flag.NewFlagSet("edit", flag.ContinueOnError)
+	cmd := flags.String("cmd", "", "open the source for this command")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	repoDir := path.Join(config.REPOS_PATH, config.REPO_NAME)
+	if !fs.Exists(repoDir) {
+		return fmt.Errorf("%v isn't cloned at %v", config.REPO_NAME, repoDir)
+	}
+	open := repoDir
+	if *cmd != "" {
+		open = target(repoDir, *cmd)
+	}
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "code"
+	}
+	c := shell.NewWithDir(repoDir, editor).WithArgs(open)
+	return c.RunAttached()
+}
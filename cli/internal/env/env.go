@@ -0,0 +1,35 @@
+package env
+
+import (
+	"os"
+	"runtime"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/git"
+	"toolbelt/pkg/table"
+)
+
+// Show prints the configuration toolbelt resolved, for debugging a new
+// machine.
+func Show(params []string) error {
+	home, _ := os.UserHomeDir()
+	configFile := config.CONFIG_PATH
+	if !fs.Exists(configFile) {
+		configFile += " (not found, using defaults)"
+	}
+	t := table.New("KEY", "VALUE")
+	t.AddRow("home", home)
+	t.AddRow("os", runtime.GOOS+"/"+runtime.GOARCH)
+	t.AddRow("config file", configFile)
+	t.AddRow("state path", config.STATE_PATH)
+	t.AddRow("dotfiles path", config.DOTFILES_PATH)
+	for _, kv := range config.Values() {
+		t.AddRow(kv[0], kv[1])
+	}
+	if dir, err := os.Getwd(); err == nil {
+		if branch, err := git.DefaultBranch(dir); err == nil {
+			t.AddRow("default branch", branch)
+		}
+	}
+	return t.Render(os.Stdout)
+}
@@ -0,0 +1,94 @@
+package env
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/notify"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/vscode"
+)
+
+type validation struct {
+	problems []string
+}
+
+func (v *validation) addf(format string, a ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, a...))
+}
+
+func (v *validation) path(name string, p string) {
+	if !fs.Exists(p) {
+		v.addf("%v %v doesn't exist", name, p)
+	}
+}
+
+func (v *validation) tool(tool string, feature string) {
+	if _, ok := shell.Which(tool); !ok {
+		v.addf("%v needs %v, which isn't on the PATH", feature, tool)
+	}
+}
+
+// Validate checks the config file and the paths and tools it points to, so
+// misconfiguration shows up before a command fails partway through.
+func Validate(params []string) error {
+	v := validation{}
+	err := config.CheckFile()
+	if err != nil {
+		v.addf("config file %v: %v", config.CONFIG_PATH, err)
+	}
+	v.path("repos_path", config.REPOS_PATH)
+	v.path("cli_path", config.CLI_PATH)
+	v.path("dotfiles path", config.DOTFILES_PATH)
+	v.path("vscode dotfiles settings", config.VSCODE_DOTFILES_SETTINGS)
+	v.path("vscode dotfiles extensions", config.VSCODE_DOTFILES_EXTENSIONS)
+	v.path("vscode settings", vscode.SettingsPath())
+	for _, d := range config.DOTFILES {
+		v.path("dotfile", path.Join(config.DOTFILES_PATH, d.Src))
+	}
+	if config.GITHUB_USERNAME == "" {
+		v.addf("github_username isn't set")
+	}
+	for _, repo := range config.REPOS {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			v.addf("repos entry %v isn't org/name", repo)
+		}
+	}
+	if _, err := regexp.Compile(config.TICKET_PATTERN); err != nil {
+		v.addf("ticket_pattern %v: %v", config.TICKET_PATTERN, err)
+	}
+	if !strings.Contains(config.TICKET_FORMAT, "{message}") {
+		v.addf("ticket_format %v doesn't contain {message}", config.TICKET_FORMAT)
+	}
+	if _, err := time.ParseDuration(config.BROWSER_OPEN_DELAY); err != nil {
+		v.addf("browser_open_delay %v: %v", config.BROWSER_OPEN_DELAY, err)
+	}
+	v.tool("git", "toolbelt")
+	v.tool("code", "dot pull and dot push")
+	if program, err := browser.Program(); err != nil {
+		v.addf("browser: %v", err)
+	} else {
+		v.tool(program, "opening URLs")
+	}
+	if config.NOTIFY {
+		if program, err := notify.Program(); err != nil {
+			v.addf("notify: %v", err)
+		} else {
+			v.tool(program, "notify")
+		}
+	}
+	if len(v.problems) == 0 {
+		fmt.Println("config is valid")
+		return nil
+	}
+	for _, p := range v.problems {
+		fmt.Printf("- %v\n", p)
+	}
+	return fmt.Errorf("found %v config problems", len(v.problems))
+}
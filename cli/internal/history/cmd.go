@@ -0,0 +1,112 @@
+package history
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/prompt"
+)
+
+// parseSince accepts a relative duration like "2h" or "3d", or an absolute
+// date like "2024-01-01" or "2024-01-01T09:00".
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %v. use a duration like 2h or 3d, or a date like 2024-01-01", value)
+}
+
+func Print(params []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	since := flags.String("since", "", "only show entries after a duration ago (2h, 3d) or a date (2024-01-01)")
+	grep := flags.String("grep", "", "only show entries whose arguments contain this substring")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	var after time.Time
+	if *since != "" {
+		after, err = parseSince(*since, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	entries, err := Read()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Time.Before(after) {
+			continue
+		}
+		if *grep != "" && !strings.Contains(strings.Join(entry.Args, " "), *grep) {
+			continue
+		}
+		status := "ok"
+		if !entry.Success {
+			status = "failed"
+		}
+		fmt.Printf("%v  %-6v  %v\n", entry.Time.Format("2006-01-02 15:04:05"), status, strings.Join(entry.Args, " "))
+	}
+	return nil
+}
+
+func Last(params []string) error {
+	flags := flag.NewFlagSet("last", flag.ContinueOnError)
+	includeFailed := flags.Bool("any", false, "re-run the most recent invocation even if it failed")
+	yes := flags.Bool("y", false, "skip confirmation")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	entries, err := Read()
+	if err != nil {
+		return err
+	}
+	var last *Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Success || *includeFailed {
+			last = &entries[i]
+			break
+		}
+	}
+	if last == nil {
+		return fmt.Errorf("no previous invocation found in history")
+	}
+	if Skip(last.Args) {
+		return fmt.Errorf("won't re-run toolbelt %v", strings.Join(last.Args, " "))
+	}
+	fmt.Printf("toolbelt %v\n", strings.Join(last.Args, " "))
+	if !*yes {
+		confirmed, err := prompt.Confirm("Run it again?", false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+	}
+	return cli.Execute(last.Args)
+}
+
+// Skip reports whether an invocation shouldn't be recorded or re-run, so
+// that `last` never re-runs itself. Global flags before the command, like
+// -y, are ignored.
+func Skip(args []string) bool {
+	command := cli.StripGlobals(args)
+	return len(command) == 0 || command[0] == "last" || command[0] == "history"
+}
@@ -0,0 +1,41 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"toolbelt/internal/config"
+)
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{}, true},
+		{[]string{"last"}, true},
+		{[]string{"-y", "last"}, true},
+		{[]string{"--trace", "last", "--any"}, true},
+		{[]string{"--plain", "history"}, true},
+		{[]string{"-y"}, true},
+		{[]string{"git", "save", "last"}, false},
+		{[]string{"-y", "git", "save"}, false},
+	}
+	for _, tt := range tests {
+		if got := Skip(tt.args); got != tt.want {
+			t.Errorf("Skip(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLastRefusesToRerunItself(t *testing.T) {
+	defer func(p string) { config.STATE_PATH = p }(config.STATE_PATH)
+	config.STATE_PATH = t.TempDir()
+	// recorded before global flags were stripped
+	if err := Record([]string{"-y", "last"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	err := Last([]string{"-y"})
+	if err == nil || !strings.Contains(err.Error(), "won't re-run") {
+		t.Errorf("got %v, want a refusal", err)
+	}
+}
@@ -0,0 +1,64 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+	"toolbelt/internal/config"
+)
+
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Args    []string  `json:"args"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func historyPath() string {
+	return path.Join(config.STATE_PATH, "history.jsonl")
+}
+
+func Record(args []string, runErr error) error {
+	entry := Entry{Time: time.Now(), Args: args, Success: runErr == nil}
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	}
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(config.STATE_PATH, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(historyPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(bytes, '\n'))
+	return err
+}
+
+func Read() ([]Entry, error) {
+	f, err := os.Open(historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package timing
+
+import "time"
+
+func childCPU() (time.Duration, time.Duration, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package timing
+
+import (
+	"syscall"
+	"time"
+)
+
+// childCPU returns the user and system CPU time used by finished child
+// processes so far.
+func childCPU() (time.Duration, time.Duration, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_CHILDREN, &usage); err != nil {
+		return 0, 0, false
+	}
+	return time.Duration(usage.Utime.Nano()), time.Duration(usage.Stime.Nano()), true
+}
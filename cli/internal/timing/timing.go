@@ -0,0 +1,30 @@
+package timing
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+	"toolbelt/pkg/cli"
+)
+
+// Run runs a toolbelt command and prints how long it took on one line.
+func Run(params []string) error {
+	args, err := cli.ParseFlags(flag.NewFlagSet("time", flag.ContinueOnError), params)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return cli.Usagef("usage: time -- <command...>")
+	}
+	userBefore, sysBefore, hasCPU := childCPU()
+	start := time.Now()
+	err = cli.Execute(args)
+	elapsed := time.Since(start)
+	line := fmt.Sprintf("toolbelt %v: %.2fs real", strings.Join(args, " "), elapsed.Seconds())
+	if userAfter, sysAfter, ok := childCPU(); ok && hasCPU {
+		line += fmt.Sprintf(", %.2fs user, %.2fs sys", (userAfter - userBefore).Seconds(), (sysAfter - sysBefore).Seconds())
+	}
+	fmt.Println(line)
+	return err
+}
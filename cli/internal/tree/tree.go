@@ -1,11 +1,29 @@
 package tree
 
 import (
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/internal/edit"
+	"toolbelt/internal/env"
+	"toolbelt/internal/history"
+	"toolbelt/internal/timing"
+	"toolbelt/internal/update"
+	"toolbelt/internal/watch"
+	"toolbelt/pkg/bootstrap"
+	"toolbelt/pkg/brew"
+	"toolbelt/pkg/cache"
+	"toolbelt/pkg/clean"
 	"toolbelt/pkg/cli"
 	"toolbelt/pkg/datadog"
+	"toolbelt/pkg/devspace"
+	"toolbelt/pkg/doctor"
+	"toolbelt/pkg/dotfile"
 	"toolbelt/pkg/git"
+	"toolbelt/pkg/jira"
 	"toolbelt/pkg/kill"
+	"toolbelt/pkg/morning"
 	"toolbelt/pkg/repo"
+	"toolbelt/pkg/repos"
 )
 
 var CmdTree = []cli.Command{
@@ -15,59 +33,454 @@ var CmdTree = []cli.Command{
 		Children: []cli.Command{
 			{
 				Name:        "save",
-				Description: "git add -A, git commit -m, and git push",
+				Order:       1,
+				Notify:      true,
+				Description: "git add -u, git commit -m, and git push. --all to also stage untracked files (or set save_add_all in the config), -e to write the message in an editor, --no-verify to skip hooks, --dir to save another repo, --fixup-lint to format until clean, --dry-run to preview, --test to only push if tests pass, --split for one commit per top-level directory, --conventional to pick a conventional commit type and scope, --copy or --open to share the pushed commit URL. with no message on a branch with an open PR, commits \"address review feedback\", and --comment also comments on the PR once pushed",
 				Run: func(params []string) error {
 					return git.Save(params)
 				},
 			},
+			{
+				Name:        "pull",
+				Timeout:     5 * time.Minute,
+				Notify:      true,
+				Description: "git pull every repo in the repos directory. --pick to choose which ones, --prune to prune deleted refs and tags first, --parallel N to limit concurrency",
+				Run: func(params []string) error {
+					return git.Pull(params)
+				},
+			},
+			{
+				Name:        "fetch-all",
+				Description: "fetch every repo without merging and report which are behind upstream. --parallel N to limit concurrency",
+				Run: func(params []string) error {
+					return repos.FetchAll(params)
+				},
+			},
+			{
+				Name:        "prune-all",
+				Description: "fetch --prune --prune-tags in every repo and report what was pruned",
+				Run: func(params []string) error {
+					return git.PruneAll(params)
+				},
+			},
+			{
+				Name:        "clean-branches",
+				Destructive: true,
+				RequiresTTY: true,
+				Description: "delete local branches already merged into the default branch. --force to include unmerged",
+				Run: func(params []string) error {
+					return git.CleanBranches(params)
+				},
+			},
+			{
+				Name:        "reviewers-request",
+				Description: "request review on the current PR from the two least-loaded reviewers",
+				Run: func(params []string) error {
+					return git.RequestReviewers(params)
+				},
+			},
+			{
+				Name:        "recent",
+				Description: "show the last 10 commits. --all-repos for the last commit of every repo",
+				Run: func(params []string) error {
+					return git.Recent(params)
+				},
+			},
+			{
+				Name:        "switch",
+				Usage:       "[branch]",
+				MaxArgs:     1,
+				Description: "check out a branch, creating it from origin if it's only remote. with no branch, pick from recent ones",
+				Run: func(params []string) error {
+					return git.Switch(params)
+				},
+			},
+			{
+				Name:        "blame-open",
+				Usage:       "<file>[:line]",
+				MinArgs:     1,
+				MaxArgs:     1,
+				Description: "open the GitHub blame for a file at the current commit",
+				Run: func(params []string) error {
+					return git.BlameOpen(params)
+				},
+			},
+			{
+				Name:        "stash",
+				Description: "find and recover stashes",
+				Children: []cli.Command{
+					{
+						Name:        "list",
+						Description: "list stashes with their age",
+						Run: func(params []string) error {
+							return git.StashList(params)
+						},
+					},
+					{
+						Name:        "pop",
+						Usage:       "[stash@{n}]",
+						MaxArgs:     1,
+						Description: "pop a stash, keeping it and listing the files if it conflicts",
+						Run: func(params []string) error {
+							return git.StashPop(params)
+						},
+					},
+					{
+						Name:        "save",
+						Usage:       "<message>",
+						MinArgs:     1,
+						Description: "stash all changes, including untracked files, with a message",
+						Run: func(params []string) error {
+							return git.StashSave(params)
+						},
+					},
+				},
+			},
 		},
 	},
 	{
 		Name:        "kill",
-		Description: "kill a process for a given port",
+		Usage:       "<port> | -i",
+		MinArgs:     1,
+		MaxArgs:     1,
+		Destructive: true,
+		Description: "kill a process for a given port. -i to choose from all listening ports",
 		Run: func(params []string) error {
 			return kill.Port(params)
 		},
+		Children: []cli.Command{
+			{
+				Name:        "name",
+				Usage:       "[--dry-run] <pattern>",
+				MinArgs:     1,
+				MaxArgs:     2,
+				Description: "kill processes whose command line matches a pattern, after confirming. --dry-run to only list them",
+				Run: func(params []string) error {
+					return kill.Name(params)
+				},
+			},
+		},
 	},
 	{
 		Name:        "dev",
-		Description: "generic development utilities",
+		Description: "generic development utilities. --repo <name> to target a repo other than the current one. test, lint, format, and setup take --all to run in every recognized repo, with --parallel N to limit concurrency (test runs one at a time by default)",
 		Children: []cli.Command{
 			{
 				Name:        "test",
+				Order:       1,
+				Notify:      true,
 				Description: "Run the tests",
 				Run: func(params []string) error {
-					return repo.Current().Test()
+					return repo.Test(params)
 				},
 			},
 			{
 				Name:        "Run",
 				Description: "Run the app locally",
 				Run: func(params []string) error {
-					return repo.Current().Run()
+					return repo.Run(params)
 				},
 			},
 			{
 				Name:        "lint",
 				Description: "Run the lint checks",
 				Run: func(params []string) error {
-					return repo.Current().Lint()
+					return repo.Lint(params)
 				},
 			},
 			{
 				Name:        "format",
 				Description: "format the repo",
 				Run: func(params []string) error {
-					return repo.Current().Format()
+					return repo.Format(params)
+				},
+			},
+			{
+				Name:        "setup",
+				Description: "install the repo's dependencies",
+				Run: func(params []string) error {
+					return repo.Setup(params)
+				},
+			},
+			{
+				Name:        "coverage",
+				Description: "run the tests with coverage. --open to view the HTML report",
+				Run: func(params []string) error {
+					return repo.Coverage(params)
+				},
+			},
+			{
+				Name:        "which",
+				Description: "print which repo the current directory was detected as",
+				Run: func(params []string) error {
+					return repo.Which(params)
+				},
+			},
+			{
+				Name:        "reviewers",
+				Description: "print the reviewers for the current repo with links to their GitHub profiles",
+				Run: func(params []string) error {
+					return repo.PrintReviewers()
+				},
+			},
+			{
+				Name:        "open-ci",
+				Description: "open the latest CI run for the current branch",
+				Run: func(params []string) error {
+					return git.OpenCI()
 				},
 			},
 		},
 	},
 	{
 		Name:        "datadog",
-		Description: "tools for the observability platform DataDog",
+		RequiresTTY: true,
+		Description: "tools for the observability platform DataDog. with no subcommand, build a query in a form",
 		Run: func(params []string) error {
 			return datadog.Form()
 		},
+		Children: []cli.Command{
+			{
+				Name:        "errors",
+				Description: "open error logs from the last 15 minutes for the configured datadog_service and datadog_env. --service, --env, --instance, and --range to override",
+				Run: func(params []string) error {
+					return datadog.Errors(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "config",
+		Description: "read and update the toolbelt config file",
+		Children: []cli.Command{
+			{
+				Name:        "get",
+				Usage:       "<key>",
+				MinArgs:     1,
+				MaxArgs:     1,
+				Description: "print the resolved value of a config key",
+				Run: func(params []string) error {
+					return config.Get(params)
+				},
+			},
+			{
+				Name:        "set",
+				Usage:       "<key> <value>",
+				MinArgs:     2,
+				MaxArgs:     2,
+				Description: "set a config key in the config file",
+				Run: func(params []string) error {
+					return config.Set(params)
+				},
+			},
+			{
+				Name:        "show",
+				Description: "print the resolved config, paths, and OS",
+				Run: func(params []string) error {
+					return env.Show(params)
+				},
+			},
+			{
+				Name:        "validate",
+				Description: "check the config file, the paths it points to, and the tools enabled features need",
+				Run: func(params []string) error {
+					return env.Validate(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "repos",
+		Description: "utilities that fan out across every repo in the repos directory",
+		Children: []cli.Command{
+			{
+				Name:        "exec",
+				Usage:       "[--parallel N] [--fail-fast] -- <command...>",
+				MinArgs:     1,
+				Description: "run a command in every repo, continuing past failures and reporting them at the end. --fail-fast to stop at the first failure, --parallel N to limit concurrency",
+				Run: func(params []string) error {
+					return repos.Exec(params)
+				},
+			},
+			{
+				Name:        "clone",
+				Description: "clone every repo in the config's repos list that isn't cloned yet, retrying failures. safe to re-run. --parallel N to limit concurrency",
+				Run: func(params []string) error {
+					return repos.Clone(params)
+				},
+			},
+			{
+				Name:        "sync",
+				Description: "fetch every repo, report ahead/behind, and fast-forward the ones that are only behind",
+				Run: func(params []string) error {
+					return repos.Sync(params)
+				},
+			},
+			{
+				Name:        "archive",
+				Description: "move repos without a commit or fetch in --days (default from config) into the archive folder, which other repos commands skip. --dry-run to only list them, -y to archive all of them",
+				Run: func(params []string) error {
+					return repos.Archive(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "history",
+		Description: "print previous toolbelt invocations. --since 2h and --grep <substr> to filter",
+		Run: func(params []string) error {
+			return history.Print(params)
+		},
+	},
+	{
+		Name:        "last",
+		Description: "re-run the most recent successful invocation. --any to include failures, -y to skip confirmation",
+		Run: func(params []string) error {
+			return history.Last(params)
+		},
+	},
+	{
+		Name:        "update",
+		Description: "pull, rebuild, and reinstall toolbelt",
+		Run: func(params []string) error {
+			return update.Run(params)
+		},
+	},
+	{
+		Name:        "dot",
+		Description: "sync dotfiles with the dotfiles repo",
+		Children: []cli.Command{
+			{
+				Name:        "pull",
+				Timeout:     5 * time.Minute,
+				Description: "copy dotfiles from the dotfiles repo into place",
+				Run: func(params []string) error {
+					return dotfile.Pull(params)
+				},
+			},
+			{
+				Name:        "push",
+				Description: "copy local dotfiles back into the dotfiles repo",
+				Run: func(params []string) error {
+					return dotfile.Push(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "morning",
+		Timeout:     10 * time.Minute,
+		Notify:      true,
+		Description: "log in to AWS if needed and pull every repo",
+		Run: func(params []string) error {
+			return morning.Run(params)
+		},
+	},
+	{
+		Name:        "doctor",
+		Description: "check that the tools toolbelt depends on are installed. --format json for scripts",
+		Run: func(params []string) error {
+			return doctor.Run(params)
+		},
+	},
+	{
+		Name:        "edit",
+		Description: "open the toolbelt source in your editor. --cmd <name> to open a command's source",
+		Run: func(params []string) error {
+			return edit.Run(params)
+		},
+	},
+	{
+		Name:        "brew",
+		Description: "homebrew utilities",
+		Children: []cli.Command{
+			{
+				Name:        "sync",
+				Description: "install everything in the dotfiles repo's Brewfile",
+				Run: func(params []string) error {
+					return brew.Sync(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "bootstrap",
+		Description: "set up a new machine: clone dotfiles, dot pull, repos clone, brew sync, and doctor",
+		Run: func(params []string) error {
+			return bootstrap.Run(params)
+		},
+	},
+	{
+		Name:        "watch",
+		Usage:       "[--interval 30s] -- <command...>",
+		MinArgs:     1,
+		Description: "re-run a toolbelt command on an interval until Ctrl-C",
+		Run: func(params []string) error {
+			return watch.Run(params)
+		},
+	},
+	{
+		Name:        "clean",
+		Description: "remove build artifact directories like node_modules and __pycache__ from the current repo. --all for every repo, --dry-run to only list them",
+		Run: func(params []string) error {
+			return clean.Run(params)
+		},
+	},
+	{
+		Name:        "time",
+		Usage:       "-- <command...>",
+		MinArgs:     1,
+		Description: "run a toolbelt command and print how long it took",
+		Run: func(params []string) error {
+			return timing.Run(params)
+		},
+	},
+	{
+		Name:        "jira",
+		Description: "open the Jira ticket for the current branch",
+		Run: func(params []string) error {
+			return jira.Open(params)
+		},
+	},
+	{
+		Name:        "diff",
+		Description: "show the current repo's staged and unstaged changes, through delta if it's installed. without a terminal, prints plain git diff for piping to git apply",
+		Run: func(params []string) error {
+			return git.Diff(params)
+		},
+	},
+	{
+		Name:        "standup",
+		Description: "list your commits across every repo since yesterday 9am, grouped by repo. --since to change the start",
+		Run: func(params []string) error {
+			return git.Standup(params)
+		},
+	},
+	{
+		Name:        "cache",
+		Description: "manage cached lookups like default branches and review load",
+		Children: []cli.Command{
+			{
+				Name:        "clear",
+				Description: "delete every cached value",
+				Run: func(params []string) error {
+					return cache.Clear(params)
+				},
+			},
+		},
+	},
+	{
+		Name:        "devspace",
+		Description: "devspace utilities",
+		Children: []cli.Command{
+			{
+				Name:        "reset",
+				Destructive: true,
+				Description: "delete and recreate the devspace namespace",
+				Run: func(params []string) error {
+					return devspace.Reset(params)
+				},
+			},
+		},
 	},
 }
@@ -0,0 +1,45 @@
+package update
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/shell"
+)
+
+func Run(params []string) error {
+	repoDir := path.Join(config.REPOS_PATH, config.REPO_NAME)
+	cliDir := path.Join(repoDir, "cli")
+	built := path.Join(cliDir, "toolbelt")
+	_, err := shell.RunCmds([]shell.Cmd{
+		shell.NewWithDir(repoDir, "git pull"),
+		shell.NewWithDir(cliDir, "go build -o toolbelt"),
+	})
+	if err != nil {
+		return err
+	}
+	dest := config.CLI_PATH
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		dest = path.Join(dest, "toolbelt")
+	}
+	old, oldErr := fs.Stat(dest)
+	next, err := fs.Stat(built)
+	if err != nil {
+		return err
+	}
+	if oldErr == nil && old.SHA == next.SHA {
+		fmt.Printf("%v is already up to date (sha256 %v)\n", dest, old.SHA)
+		return nil
+	}
+	err = fs.ReplaceFile(built, dest, 0755)
+	if err != nil {
+		return fmt.Errorf("could not install %v to %v, the existing binary was left in place: %v", built, dest, err)
+	}
+	if oldErr == nil {
+		fmt.Printf("old: %v bytes sha256 %v\n", old.Size, old.SHA)
+	}
+	fmt.Printf("new: %v bytes sha256 %v\n", next.Size, next.SHA)
+	return nil
+}
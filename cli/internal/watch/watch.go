@@ -0,0 +1,47 @@
+package watch
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"toolbelt/pkg/cli"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// Run re-runs a toolbelt command on an interval until interrupted.
+func Run(params []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := flags.Duration("interval", 30*time.Second, "how long to wait between runs, e.g. 30s or 5m")
+	args, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return cli.Usagef("usage: watch [--interval 30s] -- <command...>")
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		fmt.Print(clearScreen)
+		fmt.Printf("%v  every %v: toolbelt %v\n\n", time.Now().Format("15:04:05"), *interval, strings.Join(args, " "))
+		err = cli.Execute(args)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,17 +1,119 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/internal/history"
 	"toolbelt/internal/tree"
+	"toolbelt/pkg/browser"
 	"toolbelt/pkg/cli"
+	"toolbelt/pkg/notify"
+
+	"github.com/charmbracelet/huh"
+)
+
+// Exit codes, so scripts chaining toolbelt calls can tell failures apart.
+const (
+	exitGeneric           = 1
+	exitUsage             = 2
+	exitMissingDependency = 3
+	exitInterrupted       = 130
 )
 
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, cli.ErrUsage):
+		return exitUsage
+	case errors.Is(err, cli.ErrMissingDependency), errors.Is(err, exec.ErrNotFound):
+		return exitMissingDependency
+	case errors.Is(err, cli.ErrAborted), errors.Is(err, huh.ErrUserAborted):
+		return exitInterrupted
+	default:
+		return exitGeneric
+	}
+}
+
 func main() {
 	input := os.Args[1:] // ignore the "toolbelt" prefix
+	configPath := cli.Globals.String("config", "", "path to a config file to use instead of the default")
+	browserTemplate := cli.Globals.String("browser", "", "command template for opening URLs, with %v for the URL")
+	outputPath := cli.Globals.String("output", "", "also append everything printed to stdout and stderr to this file")
+	closeOutput := func() {}
+	cli.Setup = func() error {
+		var err error
+		if *configPath != "" {
+			err = config.LoadFrom(*configPath)
+		} else {
+			err = config.Load()
+		}
+		if err != nil {
+			return fmt.Errorf("could not load config: %v", err)
+		}
+		cli.ConfirmDestructive = config.CONFIRM_DESTRUCTIVE
+		notify.Enabled = config.NOTIFY
+		browser.Template = config.BROWSER
+		if *browserTemplate != "" {
+			browser.Template = *browserTemplate
+		}
+		if *outputPath != "" {
+			f, err := os.OpenFile(config.ExpandHome(*outputPath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return fmt.Errorf("could not open --output file: %v", err)
+			}
+			fmt.Fprintf(f, "==> toolbelt %v at %v\n", strings.Join(input, " "), time.Now().Format(time.RFC3339))
+			closeOutput, err = teeOutput(f)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	err := cli.Run(input, tree.CmdTree)
+	if !history.Skip(input) {
+		history.Record(input, err)
+	}
 	if err != nil {
 		fmt.Println(err.Error())
-		os.Exit(1)
 	}
+	closeOutput()
+	if err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+// teeOutput swaps os.Stdout and os.Stderr for pipes that copy to both the
+// original stream and f, so the log gets command output, tables, summaries,
+// and errors alike. The returned func flushes the pipes and must be called
+// before exiting.
+func teeOutput(f *os.File) (func(), error) {
+	var wg sync.WaitGroup
+	writers := []*os.File{}
+	for _, stream := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return nil, fmt.Errorf("could not tee --output: %v", err)
+		}
+		original := *stream
+		*stream = w
+		writers = append(writers, w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			io.Copy(io.MultiWriter(original, f), r)
+		}()
+	}
+	return func() {
+		for _, w := range writers {
+			w.Close()
+		}
+		wg.Wait()
+		f.Close()
+	}, nil
 }
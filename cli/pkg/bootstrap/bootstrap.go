@@ -0,0 +1,62 @@
+package bootstrap
+
+import (
+	"fmt"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/brew"
+	"toolbelt/pkg/doctor"
+	"toolbelt/pkg/dotfile"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/repos"
+	"toolbelt/pkg/shell"
+)
+
+type step struct {
+	name string
+	run  func() error
+}
+
+func cloneDotfiles() error {
+	if fs.Exists(config.DOTFILES_PATH) {
+		fmt.Printf("%v is already cloned\n", config.DOTFILES_REPO)
+		return nil
+	}
+	url := fmt.Sprintf("git@github.com:%v/%v.git", config.GITHUB_USERNAME, config.DOTFILES_REPO)
+	c := shell.NewFromArray([]string{"git", "clone", url, config.DOTFILES_PATH})
+	_, err := c.RunCmd()
+	return err
+}
+
+// Run sets up a new machine. Every step runs even if an earlier one fails,
+// and a summary is printed at the end.
+func Run(params []string) error {
+	steps := []step{
+		{"clone dotfiles", cloneDotfiles},
+		{"dot pull", func() error { return dotfile.Pull(nil) }},
+		{"repos clone", func() error { return repos.Clone(nil) }},
+		{"brew sync", func() error { return brew.Sync(nil) }},
+		{"doctor", func() error { return doctor.Run(nil) }},
+	}
+	errs := make([]error, len(steps))
+	for i, s := range steps {
+		fmt.Printf("==> [%v/%v] %v\n", i+1, len(steps), s.name)
+		errs[i] = s.run()
+		if errs[i] != nil {
+			fmt.Printf("%v failed: %v\n", s.name, errs[i])
+		}
+	}
+	fmt.Println("\nbootstrap summary:")
+	failed := 0
+	for i, s := range steps {
+		status := "ok"
+		if errs[i] != nil {
+			status = "failed"
+			failed++
+		}
+		fmt.Printf("  %-16v %v\n", s.name, status)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%v of %v bootstrap steps failed", failed, len(steps))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package brew
+
+import (
+	"fmt"
+	"path"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/shell"
+)
+
+func brewfile() string {
+	return path.Join(config.DOTFILES_PATH, "Brewfile")
+}
+
+// Sync installs everything in the dotfiles repo's Brewfile.
+func Sync(params []string) error {
+	file := brewfile()
+	if !fs.Exists(file) {
+		return fmt.Errorf("no Brewfile at %v", file)
+	}
+	c := shell.NewFromArray([]string{"brew", "bundle", "--file", file})
+	return c.RunAttached()
+}
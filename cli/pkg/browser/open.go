@@ -4,17 +4,54 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"strings"
+	"toolbelt/pkg/shell"
 )
 
+// Template, when set, is the command used to open URLs with %v standing in
+// for the URL, e.g. `open -a "Google Chrome" --args --profile-directory=Work %v`.
+var Template = ""
+
 func Open(url string) error {
+	args, err := command(url)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Errorf("can't open %v: %v not found", url, args[0])
+	}
+	err = exec.Command(args[0], args[1:]...).Start()
+	if err != nil {
+		return fmt.Errorf("can't open %v with %v: %v", url, args[0], err)
+	}
+	return nil
+}
+
+// Program is the tool Open runs, from Template or the OS default.
+func Program() (string, error) {
+	args, err := command("")
+	if err != nil {
+		return "", err
+	}
+	return args[0], nil
+}
+
+func command(url string) ([]string, error) {
+	if Template != "" {
+		args := shell.Split(strings.Replace(Template, "%v", url, 1))
+		if len(args) == 0 {
+			return nil, fmt.Errorf("browser template is empty")
+		}
+		return args, nil
+	}
 	switch runtime.GOOS {
 	case "linux":
-		return exec.Command("xdg-open", url).Start()
+		return []string{"xdg-open", url}, nil
 	case "windows":
-		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}, nil
 	case "darwin":
-		return exec.Command("open", url).Start()
+		return []string{"open", url}, nil
 	default:
-		return fmt.Errorf("unsupported platform")
+		return nil, fmt.Errorf("can't open %v: unsupported platform %v", url, runtime.GOOS)
 	}
 }
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sync"
+	"time"
+	"toolbelt/internal/config"
+)
+
+type entry struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+var mu sync.Mutex
+
+func cachePath() string {
+	return path.Join(config.STATE_PATH, "cache.json")
+}
+
+func read() map[string]entry {
+	entries := map[string]entry{}
+	bytes, err := os.ReadFile(cachePath())
+	if err != nil {
+		return entries
+	}
+	json.Unmarshal(bytes, &entries)
+	return entries
+}
+
+func write(entries map[string]entry) error {
+	err := os.MkdirAll(config.STATE_PATH, 0755)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp := cachePath() + ".tmp"
+	err = os.WriteFile(tmp, bytes, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, cachePath())
+}
+
+// Get returns the cached value for key if it hasn't expired.
+func Get(key string) (string, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	e, ok := read()[key]
+	if !ok || time.Now().After(e.Expires) {
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Set caches value for key for ttl. Expired entries are dropped while
+// writing.
+func Set(key string, value string, ttl time.Duration) error {
+	mu.Lock()
+	defer mu.Unlock()
+	entries := read()
+	now := time.Now()
+	for k, e := range entries {
+		if now.After(e.Expires) {
+			delete(entries, k)
+		}
+	}
+	entries[key] = entry{Value: value, Expires: now.Add(ttl)}
+	return write(entries)
+}
+
+// Lookup returns the cached value for key, or calls fetch and caches its
+// result for ttl. A failure to write the cache isn't an error.
+func Lookup(key string, ttl time.Duration, fetch func() (string, error)) (string, error) {
+	if value, ok := Get(key); ok {
+		return value, nil
+	}
+	value, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	Set(key, value, ttl)
+	return value, nil
+}
+
+func Clear(params []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	err := os.Remove(cachePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package clean
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/comparable"
+	tfs "toolbelt/pkg/fs"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/repo"
+)
+
+type artifact struct {
+	path string
+	size int64
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// find returns every artifact directory under root, without descending into
+// them or into .git.
+func find(root string) ([]artifact, error) {
+	artifacts := []artifact{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() || p == root {
+			return nil
+		}
+		if d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if comparable.Includes(config.CLEAN_DIRS, d.Name()) {
+			artifacts = append(artifacts, artifact{path: p, size: dirSize(p)})
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return artifacts, err
+}
+
+func currentRoot() (string, error) {
+	root, err := repo.Root("")
+	if err != nil {
+		return "", fmt.Errorf("not in a git repo. pass --all to clean every repo")
+	}
+	return root, nil
+}
+
+func confirm(total int64) error {
+	confirmed, err := prompt.Confirm(fmt.Sprintf("Remove %v of build artifacts?", tfs.HumanSize(total)), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cli.ErrAborted
+	}
+	return nil
+}
+
+func Run(params []string) error {
+	flags := flag.NewFlagSet("clean", flag.ContinueOnError)
+	all := flags.Bool("all", false, "clean every repo instead of the current one")
+	dryRun := flags.Bool("dry-run", false, "list what would be removed without removing it")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	roots := []string{}
+	if *all {
+		roots, err = repo.Dirs()
+	} else {
+		var root string
+		root, err = currentRoot()
+		roots = append(roots, root)
+	}
+	if err != nil {
+		return err
+	}
+	artifacts := []artifact{}
+	var total int64
+	for _, root := range roots {
+		found, err := find(root)
+		if err != nil {
+			return err
+		}
+		for _, a := range found {
+			fmt.Printf("%10v  %v\n", tfs.HumanSize(a.size), a.path)
+			total += a.size
+		}
+		artifacts = append(artifacts, found...)
+	}
+	if len(artifacts) == 0 {
+		fmt.Println("nothing to clean")
+		return nil
+	}
+	fmt.Printf("%10v  total\n", tfs.HumanSize(total))
+	if *dryRun {
+		return nil
+	}
+	err = confirm(total)
+	if err != nil {
+		return err
+	}
+	var reclaimed int64
+	for _, a := range artifacts {
+		err = os.RemoveAll(a.path)
+		if err != nil {
+			fmt.Printf("couldn't remove %v: %v\n", a.path, err)
+			continue
+		}
+		reclaimed += a.size
+	}
+	fmt.Printf("reclaimed %v\n", tfs.HumanSize(reclaimed))
+	return nil
+}
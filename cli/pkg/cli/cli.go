@@ -1,12 +1,79 @@
 package cli
 
-import "fmt"
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"toolbelt/pkg/notify"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/tty"
+)
 
 type Command struct {
 	Name        string
 	Description string
 	Children    []Command
 	Run         func(params []string) error
+	// Order pins a command above its siblings in help output. Lower values
+	// come first and zero means unpinned.
+	Order int
+	// Destructive commands ask for confirmation first when
+	// ConfirmDestructive is on.
+	Destructive bool
+	// RequiresTTY commands fail fast instead of hanging on a prompt when
+	// stdin or stdout isn't a terminal.
+	RequiresTTY bool
+	// Usage describes the arguments, e.g. "<key> <value>". MinArgs and
+	// MaxArgs bound how many tokens are left after the command path, flags
+	// included. A MaxArgs of zero means no limit.
+	Usage   string
+	MinArgs int
+	MaxArgs int
+	// Notify sends a desktop notification when the command finishes, if
+	// notifications are enabled.
+	Notify bool
+	// Timeout stops the command and every process it started once it has
+	// run this long. Zero means no timeout.
+	Timeout time.Duration
+}
+
+var ConfirmDestructive = false
+
+// SortCommands lists help output as pinned commands, then branches, then
+// leaves, each alphabetical. When false, the tree's literal order is kept.
+var SortCommands = true
+
+func sorted(cmds []Command) []Command {
+	if !SortCommands {
+		return cmds
+	}
+	result := append([]Command{}, cmds...)
+	rank := func(cmd Command) int {
+		if cmd.Order != 0 {
+			return 0
+		}
+		if len(cmd.Children) > 0 {
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.Name < b.Name
+	})
+	return result
 }
 
 func findCmd(input string, cmds []Command) (*Command, error) {
@@ -15,39 +82,212 @@ func findCmd(input string, cmds []Command) (*Command, error) {
 			return &cmd, nil
 		}
 	}
-	return nil, fmt.Errorf("invalid input. %v is not valid", input)
+	return nil, Usagef("invalid input. %v is not valid", input)
 }
 
 func printDescription(cmds []Command) {
-	for _, cmd := range cmds {
+	for _, cmd := range sorted(cmds) {
 		line := fmt.Sprintf("%v: %v", cmd.Name, cmd.Description)
 		fmt.Println(line)
 	}
 }
 
+// printTree prints every command below cmds, indented by depth. Branches
+// are marked with "+" and runnable leaves with "-".
+func printTree(cmds []Command, depth int) {
+	for _, cmd := range sorted(cmds) {
+		marker := "-"
+		if len(cmd.Children) > 0 {
+			marker = "+"
+		}
+		fmt.Printf("%v%v %v: %v\n", strings.Repeat("  ", depth), marker, cmd.Name, cmd.Description)
+		printTree(cmd.Children, depth+1)
+	}
+}
+
+// printLeaves prints the full path of every runnable command below cmds,
+// one per line.
+func printLeaves(cmds []Command, prefix []string) {
+	for _, cmd := range sorted(cmds) {
+		path := append(append([]string{}, prefix...), cmd.Name)
+		if cmd.Run != nil {
+			fmt.Println(strings.Join(path, " "))
+		}
+		printLeaves(cmd.Children, path)
+	}
+}
+
+var root []Command
+
+// Globals holds flags that come before the command path, e.g.
+// `toolbelt --tree`. Callers may register more before calling Run.
+var Globals = flag.NewFlagSet("toolbelt", flag.ContinueOnError)
+
+var showTree = Globals.Bool("tree", false, "print the full command hierarchy")
+var showList = Globals.Bool("list", false, "print the path of every runnable command, one per line")
+var repeat = Globals.Int("repeat", 1, "run the command this many times, stopping at the first failure")
+var untilFail = Globals.Bool("until-fail", false, "run the command until it fails, at most --repeat times if given")
+var trace = Globals.Bool("trace", false, "print how the command path was resolved to stderr")
+
+func tracef(format string, a ...any) {
+	if *trace {
+		fmt.Fprintf(os.Stderr, "trace: "+format+"\n", a...)
+	}
+}
+
+func init() {
+	Globals.BoolVar(&tty.Plain, "plain", false, "never prompt. commands take their non-interactive defaults")
+	Globals.BoolVar(&tty.Plain, "non-interactive", false, "same as --plain")
+	Globals.BoolVar(&prompt.AssumeYes, "y", false, "answer yes to every confirmation")
+}
+
+// StripGlobals returns args without the leading global flags, i.e. the
+// command path and its params, without touching Globals.
+func StripGlobals(args []string) []string {
+	flags := flag.NewFlagSet("toolbelt", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	Globals.VisitAll(func(f *flag.Flag) {
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			flags.Bool(f.Name, false, "")
+		} else {
+			flags.String(f.Name, "", "")
+		}
+	})
+	if err := flags.Parse(args); err != nil {
+		return args
+	}
+	return flags.Args()
+}
+
+// Setup, when set, runs once after the global flags are parsed and before
+// any command, e.g. to load config from a path given by a global flag.
+var Setup func() error
+
+// Execute runs input against the tree passed to the outermost Run, so
+// commands can dispatch other commands without referencing the tree.
+func Execute(input []string) error {
+	return Run(input, root)
+}
+
 func Run(input []string, tree []Command) error {
+	outermost := root == nil
+	if outermost {
+		root = tree
+	}
+	err := Globals.Parse(input)
+	if err != nil {
+		return Usagef("%v", err)
+	}
+	input = Globals.Args()
+	if outermost && Setup != nil {
+		err = Setup()
+		if err != nil {
+			return err
+		}
+	}
+	if *showTree {
+		printTree(tree, 0)
+		return nil
+	}
+	if *showList {
+		printLeaves(tree, nil)
+		return nil
+	}
 	if len(input) == 0 {
 		printDescription(tree)
 		return nil
 	}
+	if outermost && (*repeat > 1 || *untilFail) {
+		return repeatDispatch(input, tree)
+	}
+	return dispatch(input, tree)
+}
+
+func dispatch(input []string, tree []Command) error {
+	var err error
+	// Everything from "--" on is never matched against the tree and reaches
+	// the command verbatim, so `dev test -- -v` forwards -v to the test
+	// runner.
+	path := input
+	passthrough := []string{}
+	for j, val := range input {
+		if val == "--" {
+			path = input[:j]
+			passthrough = input[j:]
+			break
+		}
+	}
 	curr := tree
 	var cmd *Command
-	var err error
 	i := 0
-	for _, val := range input {
-		cmd, err = findCmd(val, curr)
-		i += 1
+	for _, val := range path {
+		next, err := findCmd(val, curr)
 		if err != nil {
+			// A command with both Run and Children, like `kill`, takes
+			// anything that isn't a child as its own params.
+			if cmd != nil && cmd.Run != nil {
+				tracef("no child %q, running %q", val, cmd.Name)
+				break
+			}
+			tracef("no match for %q", val)
 			return err
 		}
+		cmd = next
+		i += 1
 		if cmd == nil || cmd.Children == nil || len(cmd.Children) == 0 {
+			tracef("matched %q -> leaf", val)
 			break
 		}
+		tracef("matched %q -> descending", val)
 		curr = cmd.Children
 	}
+	if cmd == nil {
+		printDescription(tree)
+		return nil
+	}
 	if cmd.Run == nil {
 		printDescription(cmd.Children)
 		return nil
 	}
-	return cmd.Run(input[i:])
+	params := append(append([]string{}, path[i:]...), passthrough...)
+	tracef("remaining params: %q", params)
+	if len(params) < cmd.MinArgs || (cmd.MaxArgs > 0 && len(params) > cmd.MaxArgs) {
+		return Usagef("usage: toolbelt %v %v", strings.Join(input[:i], " "), cmd.Usage)
+	}
+	if cmd.RequiresTTY && !tty.IsInteractive() {
+		return fmt.Errorf("%v needs an interactive terminal", strings.Join(input[:i], " "))
+	}
+	if cmd.Destructive && ConfirmDestructive {
+		confirmed, err := prompt.Confirm(fmt.Sprintf("%v is destructive. Continue?", strings.Join(input[:i], " ")), false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return ErrAborted
+		}
+	}
+	name := strings.Join(input[:i], " ")
+	if cmd.Timeout > 0 {
+		err = runWithTimeout(*cmd, params, name)
+	} else {
+		err = cmd.Run(params)
+	}
+	if cmd.Notify {
+		notify.Done(name, err)
+	}
+	return err
+}
+
+// runWithTimeout runs cmd with every process it starts bound to a deadline,
+// and waits for cmd to return so nothing it started outlives the timeout.
+func runWithTimeout(cmd Command, params []string, name string) error {
+	ctx, cancel := context.WithTimeout(shell.Context(), cmd.Timeout)
+	defer cancel()
+	err := shell.WithContext(ctx, func() error {
+		return cmd.Run(params)
+	})
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%v timed out after %v", name, cmd.Timeout)
+	}
+	return err
 }
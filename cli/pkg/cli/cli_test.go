@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDispatchParentWithChildren(t *testing.T) {
+	var ran string
+	var got []string
+	tree := []Command{{
+		Name:    "kill",
+		MinArgs: 1,
+		MaxArgs: 1,
+		Run: func(params []string) error {
+			ran, got = "kill", params
+			return nil
+		},
+		Children: []Command{{
+			Name: "name",
+			Run: func(params []string) error {
+				ran, got = "name", params
+				return nil
+			},
+		}},
+	}}
+	tests := []struct {
+		input  []string
+		ran    string
+		params []string
+	}{
+		{[]string{"kill", "3000"}, "kill", []string{"3000"}},
+		{[]string{"kill", "-i"}, "kill", []string{"-i"}},
+		{[]string{"kill", "name", "--dry-run", "node"}, "name", []string{"--dry-run", "node"}},
+	}
+	for _, tt := range tests {
+		ran, got = "", nil
+		err := dispatch(tt.input, tree)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.input, err)
+		}
+		if ran != tt.ran || !reflect.DeepEqual(got, tt.params) {
+			t.Errorf("%q ran %v with %q, want %v with %q", tt.input, ran, got, tt.ran, tt.params)
+		}
+	}
+	if err := dispatch([]string{"kill", "1", "2"}, tree); err == nil {
+		t.Error("expected a usage error past MaxArgs")
+	}
+}
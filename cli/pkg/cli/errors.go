@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of failure that main maps to distinct exit codes. Match them with
+// errors.Is.
+var (
+	ErrUsage             = errors.New("usage error")
+	ErrMissingDependency = errors.New("missing dependency")
+	ErrAborted           = errors.New("aborted")
+)
+
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Usagef returns an error for input the command can't accept.
+func Usagef(format string, a ...interface{}) error {
+	return &kindError{kind: ErrUsage, msg: fmt.Sprintf(format, a...)}
+}
+
+// MissingDependencyf returns an error for a required tool that isn't
+// installed.
+func MissingDependencyf(format string, a ...interface{}) error {
+	return &kindError{kind: ErrMissingDependency, msg: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+)
+
+// ParseFlags parses fs from params while allowing flags and positional
+// arguments to be interleaved, e.g. `git save "msg" --no-verify`.
+// Everything after a bare "--" is treated as positional.
+func ParseFlags(fs *flag.FlagSet, params []string) ([]string, error) {
+	positional := []string{}
+	for len(params) > 0 {
+		if err := fs.Parse(params); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, Usagef("%v", err)
+		}
+		rest := fs.Args()
+		consumed := len(params) - len(rest)
+		if consumed > 0 && params[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		params = rest[1:]
+	}
+	return positional, nil
+}
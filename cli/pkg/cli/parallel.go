@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParallelFlag parses a --parallel value. "0" and "max" mean unbounded.
+type ParallelFlag struct {
+	N int
+}
+
+func (p *ParallelFlag) String() string {
+	return strconv.Itoa(p.N)
+}
+
+func (p *ParallelFlag) Set(value string) error {
+	if value == "max" {
+		p.N = 0
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("must be a non-negative number or max")
+	}
+	p.N = n
+	return nil
+}
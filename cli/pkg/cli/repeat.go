@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// repeatDispatch runs input over and over for --repeat and --until-fail,
+// stopping at the first failure, and prints a tally.
+func repeatDispatch(input []string, tree []Command) error {
+	passed := 0
+	var err error
+	unbounded := *untilFail && *repeat <= 1
+	for i := 0; unbounded || i < *repeat; i++ {
+		fmt.Printf("==> run %v: toolbelt %v\n", i+1, strings.Join(input, " "))
+		err = dispatch(input, tree)
+		if err != nil {
+			break
+		}
+		passed++
+	}
+	failed := 0
+	if err != nil {
+		failed = 1
+	}
+	fmt.Printf("%v passed, %v failed\n", passed, failed)
+	return err
+}
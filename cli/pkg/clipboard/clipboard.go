@@ -0,0 +1,75 @@
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+type tool struct {
+	copy  []string
+	paste []string
+}
+
+func tools() []tool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []tool{
+			{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+		}
+	case "linux":
+		return []tool{
+			{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}},
+			{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}},
+			{copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}},
+		}
+	case "windows":
+		return []tool{
+			{copy: []string{"clip"}, paste: []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}},
+		}
+	default:
+		return nil
+	}
+}
+
+func find(pick func(tool) []string) ([]string, error) {
+	for _, t := range tools() {
+		args := pick(t)
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return args, nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard tool found for %v", runtime.GOOS)
+}
+
+func Copy(text string) error {
+	args, err := find(func(t tool) []string { return t.copy })
+	if err != nil {
+		return err
+	}
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdin = strings.NewReader(text)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("could not copy to clipboard with %v: %v %v", args[0], err, stderr.String())
+	}
+	return nil
+}
+
+func Paste() (string, error) {
+	args, err := find(func(t tool) []string { return t.paste })
+	if err != nil {
+		return "", err
+	}
+	c := exec.Command(args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("could not paste from clipboard with %v: %v %v", args[0], err, stderr.String())
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,11 @@
+package comparable
+
+func Filter[T any](slice []T, keep func(T) bool) []T {
+	result := []T{}
+	for _, v := range slice {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
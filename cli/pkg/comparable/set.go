@@ -14,3 +14,17 @@ func Subtract[T comparable](left []T, right []T) []T {
 	}
 	return result
 }
+
+// Unique returns slice without repeats, keeping the first occurrence of each
+// item.
+func Unique[T comparable](slice []T) []T {
+	result := []T{}
+	seen := make(map[T]bool)
+	for _, item := range slice {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
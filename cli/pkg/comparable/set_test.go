@@ -0,0 +1,13 @@
+package comparable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	got := Unique([]int{3, 1, 3, 2, 1})
+	if want := []int{3, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package datadog
+
+import (
+	"flag"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+)
+
+// Errors opens the logs page filtered to errors for the configured default
+// service and environment, skipping the form.
+func Errors(params []string) error {
+	opts, err := errorsOptions(params)
+	if err != nil {
+		return err
+	}
+	return openAll([]string{buildLogsURL(opts)})
+}
+
+func errorsOptions(params []string) (queryOptions, error) {
+	flags := flag.NewFlagSet("datadog errors", flag.ContinueOnError)
+	service := flags.String("service", config.DATADOG_SERVICE, "comma-separated services to filter to")
+	envId := flags.String("env", config.DATADOG_ENV, "comma-separated environment ids to filter to")
+	instance := flags.String("instance", config.DATADOG_INSTANCE, "the DataDog instance, e.g. dbtlabsmt")
+	timeRange := flags.String("range", "15-minute", "how far back to look, e.g. live, 1-hour, or 2-day")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return queryOptions{}, err
+	}
+	err = validateTimeRange(*timeRange)
+	if err != nil {
+		return queryOptions{}, err
+	}
+	opts := queryOptions{
+		envId:           *envId,
+		services:        splitList(*service),
+		datadogInstance: *instance,
+		timeRange:       *timeRange,
+		logStatus:       []string{"error"},
+	}
+	if *timeRange != "live" {
+		opts.start, opts.end = getTimeRangeUnixTimestamps(*timeRange)
+	}
+	return opts, nil
+}
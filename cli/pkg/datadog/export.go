@@ -0,0 +1,69 @@
+package datadog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type queryExport struct {
+	Page      string `json:"page"`
+	Query     string `json:"query"`
+	TimeRange string `json:"time_range"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	FromTs    int64  `json:"from_ts,omitempty"`
+	ToTs      int64  `json:"to_ts,omitempty"`
+}
+
+func newQueryExport(page string, query []string, opts queryOptions) queryExport {
+	export := queryExport{
+		Page:      page,
+		Query:     strings.TrimRight(strings.Join(query, " "), " "),
+		TimeRange: opts.timeRange,
+	}
+	if opts.timeRange != "live" {
+		export.From = time.UnixMilli(opts.start).Format(time.RFC3339)
+		export.To = time.UnixMilli(opts.end).Format(time.RFC3339)
+		export.FromTs = opts.start
+		export.ToTs = opts.end
+	}
+	return export
+}
+
+func printJSON(exports []queryExport) error {
+	bytes, err := json.MarshalIndent(exports, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bytes))
+	return nil
+}
+
+func printCSV(exports []queryExport) error {
+	w := csv.NewWriter(os.Stdout)
+	err := w.Write([]string{"page", "query", "time_range", "from", "to", "from_ts", "to_ts"})
+	if err != nil {
+		return err
+	}
+	for _, e := range exports {
+		err = w.Write([]string{
+			e.Page,
+			e.Query,
+			e.TimeRange,
+			e.From,
+			e.To,
+			strconv.FormatInt(e.FromTs, 10),
+			strconv.FormatInt(e.ToTs, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -2,50 +2,15 @@ package datadog
 
 import (
 	"fmt"
-	"net/url"
-	"strconv"
 	"strings"
 	"time"
+	"toolbelt/internal/config"
 	"toolbelt/pkg/browser"
 	"toolbelt/pkg/comparable"
 
 	"github.com/charmbracelet/huh"
 )
 
-func getStructuredLogQuery(service string, key string, value string) string {
-	if service == "semantic-layer-gateway" {
-		return fmt.Sprintf("@%v:%v", key, value)
-	} else if service == "metricflow-server" || service == "semantic-layer-gsheets" {
-		return fmt.Sprintf("@extra.%v:%v", key, value)
-	}
-	return ""
-}
-
-func getTimeRangeUnixTimestamps(timeRange string) (int64, int64) {
-	granularity := strings.Split(timeRange, "-")
-	intValue, _ := strconv.Atoi(granularity[0])
-	var timeGrain time.Duration
-	if granularity[1] == "minute" {
-		timeGrain = time.Minute
-	} else if granularity[1] == "hour" {
-		timeGrain = time.Hour
-	} else if granularity[1] == "day" {
-		timeGrain = time.Hour * 24
-	}
-	now := time.Now()
-	start := now.Add(-(timeGrain * time.Duration(intValue))).UnixMilli()
-	return start, now.UnixMilli()
-}
-
-func getQueryUrlParam(query []string) string {
-	encodedQuery := url.QueryEscape(strings.TrimRight(strings.Join(query, " "), " "))
-	var queryUrlParam = ""
-	if encodedQuery != "" {
-		queryUrlParam = fmt.Sprintf("query=%v&", encodedQuery)
-	}
-	return queryUrlParam
-}
-
 func getStatuses(pages []string) ([]string, []string, error) {
 	var (
 		logStatus   []string
@@ -92,12 +57,17 @@ func Form() error {
 		errorMessage    string
 		timeRange       string
 		pages           []string
+		outputs         = []string{"browser"}
 	)
+	timeRangeOptions := []huh.Option[string]{}
+	for _, r := range timeRanges {
+		timeRangeOptions = append(timeRangeOptions, huh.NewOption(r.label, r.value))
+	}
 
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewInput().Title("Environment Id").Value(&envId),
-			huh.NewInput().Title("Account Id").Value(&accountId),
+			huh.NewInput().Title("Environment Id").Description("comma-separated for multiple").Value(&envId),
+			huh.NewInput().Title("Account Id").Description("comma-separated for multiple").Value(&accountId),
 			huh.NewMultiSelect[string]().
 				Title("Service").
 				Options(
@@ -122,17 +92,7 @@ func Form() error {
 				Value(&datadogInstance),
 			huh.NewSelect[string]().
 				Title("Time Range").
-				Options(
-					huh.NewOption("Live", "live"),
-					huh.NewOption("Past 15 minutes", "15-minute"),
-					huh.NewOption("Past 1 hour", "1-hour"),
-					huh.NewOption("Past 4 hours", "4-hour"),
-					huh.NewOption("Past 1 day", "1-day"),
-					huh.NewOption("Past 2 days", "2-day"),
-					huh.NewOption("Past 3 days", "3-day"),
-					huh.NewOption("Past 7 days", "7-day"),
-					huh.NewOption("Past 15 days", "15-day"),
-				).
+				Options(timeRangeOptions...).
 				Value(&timeRange),
 			huh.NewMultiSelect[string]().
 				Title("Page").
@@ -143,6 +103,14 @@ func Form() error {
 			huh.NewText().
 				Title("Error Message").
 				Value(&errorMessage),
+			huh.NewMultiSelect[string]().
+				Title("Output").
+				Value(&outputs).
+				Options(
+					huh.NewOption("Open in browser", "browser"),
+					huh.NewOption("Print JSON", "json"),
+					huh.NewOption("Print CSV", "csv"),
+				),
 		),
 	)
 	err := form.Run()
@@ -155,65 +123,70 @@ func Form() error {
 		return err
 	}
 
-	query := []string{}
-	if len(services) > 0 {
-		expression := strings.Join(services, " OR ")
-		query = append(query, fmt.Sprintf("service:(%v)", expression))
-	}
-	structuredLogQueries := []string{}
-	for _, service := range services {
-		if envId != "" {
-			structuredLogQueries = append(
-				structuredLogQueries, getStructuredLogQuery(service, "environment_id", envId),
-			)
-		}
-		if accountId != "" {
-			structuredLogQueries = append(
-				structuredLogQueries, getStructuredLogQuery(service, "account_id", accountId),
-			)
-		}
+	opts := queryOptions{
+		envId:           envId,
+		accountId:       accountId,
+		services:        services,
+		datadogInstance: datadogInstance,
+		errorMessage:    errorMessage,
+		timeRange:       timeRange,
+		logStatus:       logStatus,
+		traceStatus:     traceStatus,
 	}
-	if len(structuredLogQueries) > 0 {
-		query = append(query, "("+strings.Join(structuredLogQueries, " OR ")+")")
-	}
-	if errorMessage != "" {
-		query = append(query, errorMessage+" ")
+	if timeRange != "live" {
+		opts.start, opts.end = getTimeRangeUnixTimestamps(timeRange)
 	}
+	exports := []queryExport{}
+	urls := []string{}
 	if comparable.Includes(pages, "logs") {
-		logsQuery := make([]string, len(query))
-		copy(logsQuery, query)
-		if len(logStatus) > 0 {
-			expression := strings.Join(logStatus, " OR ")
-			logsQuery = append(logsQuery, fmt.Sprintf("status:(%v)", expression))
+		exports = append(exports, newQueryExport("logs", buildLogsQuery(opts), opts))
+		urls = append(urls, buildLogsURL(opts))
+	}
+	if comparable.Includes(pages, "traces") {
+		exports = append(exports, newQueryExport("traces", buildTracesQuery(opts), opts))
+		urls = append(urls, buildTracesURL(opts))
+	}
+	if comparable.Includes(outputs, "browser") {
+		err = openAll(urls)
+		if err != nil {
+			return err
 		}
-		queryUrlParam := getQueryUrlParam(logsQuery)
-		timeRangeUrlParam := ""
-		liveTail := ""
-		if timeRange == "live" {
-			liveTail = "/livetail"
-		} else {
-			start, end := getTimeRangeUnixTimestamps(timeRange)
-			timeRangeUrlParam = fmt.Sprintf("from_ts=%v&to_ts=%v&", start, end)
+	}
+	if comparable.Includes(outputs, "json") {
+		err = printJSON(exports)
+		if err != nil {
+			return err
 		}
-		logsUrl := fmt.Sprintf("https://%v.datadoghq.com/logs%v?%v%v", datadogInstance, liveTail, timeRangeUrlParam, queryUrlParam)
-		browser.Open(logsUrl)
 	}
-	if comparable.Includes(pages, "traces") {
-		if len(traceStatus) > 0 {
-			expression := strings.Join(traceStatus, " OR ")
-			query = append(query, fmt.Sprintf("status:(%v)", expression))
+	if comparable.Includes(outputs, "csv") {
+		err = printCSV(exports)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openAll opens each URL in the browser, pausing between them so the
+// browser doesn't drop any.
+func openAll(urls []string) error {
+	delay, err := time.ParseDuration(config.BROWSER_OPEN_DELAY)
+	if err != nil {
+		return fmt.Errorf("invalid browser_open_delay %v: %v", config.BROWSER_OPEN_DELAY, err)
+	}
+	errs := []string{}
+	for i, url := range urls {
+		if i > 0 {
+			time.Sleep(delay)
 		}
-		queryUrlParam := getQueryUrlParam(query)
-		timeRangeUrlParam := ""
-		historicalData := true
-		if timeRange == "live" {
-			historicalData = false
-		} else {
-			start, end := getTimeRangeUnixTimestamps(timeRange)
-			timeRangeUrlParam = fmt.Sprintf("start=%v&end=%v&", start, end)
+		err := browser.Open(url)
+		if err != nil {
+			fmt.Printf("open this URL manually: %v\n", url)
+			errs = append(errs, err.Error())
 		}
-		tracesUrl := fmt.Sprintf("https://%v.datadoghq.com/apm/traces?%v%vhistoricalData=%v", datadogInstance, timeRangeUrlParam, queryUrlParam, historicalData)
-		browser.Open(tracesUrl)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
 	}
 	return nil
 }
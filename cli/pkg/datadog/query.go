@@ -0,0 +1,194 @@
+package datadog
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/comparable"
+)
+
+type queryOptions struct {
+	envId           string
+	accountId       string
+	services        []string
+	datadogInstance string
+	errorMessage    string
+	timeRange       string
+	logStatus       []string
+	traceStatus     []string
+	start           int64
+	end             int64
+}
+
+// ELB access logs don't carry environment or account ids, so "elb" is
+// intentionally absent and is only ever filtered by service.
+var structuredLogPrefixes = map[string]string{
+	"semantic-layer-gateway": "@",
+	"metricflow-server":      "@extra.",
+	"semantic-layer-gsheets": "@extra.",
+}
+
+func hasStructuredLogs(service string) bool {
+	_, ok := structuredLogPrefixes[service]
+	return ok
+}
+
+func getStructuredLogQuery(service string, key string, values []string) string {
+	prefix, ok := structuredLogPrefixes[service]
+	if !ok {
+		return ""
+	}
+	terms := []string{}
+	for _, value := range values {
+		terms = append(terms, fmt.Sprintf("%v%v:%v", prefix, key, value))
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// timeRanges are the accepted time ranges, as offered by the form.
+var timeRanges = []struct {
+	value string
+	label string
+}{
+	{"live", "Live"},
+	{"15-minute", "Past 15 minutes"},
+	{"1-hour", "Past 1 hour"},
+	{"4-hour", "Past 4 hours"},
+	{"1-day", "Past 1 day"},
+	{"2-day", "Past 2 days"},
+	{"3-day", "Past 3 days"},
+	{"7-day", "Past 7 days"},
+	{"15-day", "Past 15 days"},
+}
+
+func validateTimeRange(timeRange string) error {
+	values := []string{}
+	for _, r := range timeRanges {
+		if r.value == timeRange {
+			return nil
+		}
+		values = append(values, r.value)
+	}
+	return cli.Usagef("invalid time range %v. use one of %v", timeRange, strings.Join(values, ", "))
+}
+
+func getTimeRangeUnixTimestamps(timeRange string) (int64, int64) {
+	granularity := strings.Split(timeRange, "-")
+	intValue, _ := strconv.Atoi(granularity[0])
+	var timeGrain time.Duration
+	if granularity[1] == "minute" {
+		timeGrain = time.Minute
+	} else if granularity[1] == "hour" {
+		timeGrain = time.Hour
+	} else if granularity[1] == "day" {
+		timeGrain = time.Hour * 24
+	}
+	now := time.Now()
+	start := now.Add(-(timeGrain * time.Duration(intValue))).UnixMilli()
+	return start, now.UnixMilli()
+}
+
+func getQueryUrlParam(query []string) string {
+	encodedQuery := url.QueryEscape(strings.TrimRight(strings.Join(query, " "), " "))
+	var queryUrlParam = ""
+	if encodedQuery != "" {
+		queryUrlParam = fmt.Sprintf("query=%v&", encodedQuery)
+	}
+	return queryUrlParam
+}
+
+func buildQuery(opts queryOptions) []string {
+	query := []string{}
+	if len(opts.services) > 0 {
+		expression := strings.Join(opts.services, " OR ")
+		query = append(query, fmt.Sprintf("service:(%v)", expression))
+	}
+	envIds := splitList(opts.envId)
+	accountIds := splitList(opts.accountId)
+	structuredLogQueries := []string{}
+	for _, service := range opts.services {
+		if !hasStructuredLogs(service) {
+			continue
+		}
+		if len(envIds) > 0 {
+			structuredLogQueries = append(
+				structuredLogQueries, getStructuredLogQuery(service, "environment_id", envIds),
+			)
+		}
+		if len(accountIds) > 0 {
+			structuredLogQueries = append(
+				structuredLogQueries, getStructuredLogQuery(service, "account_id", accountIds),
+			)
+		}
+	}
+	structuredLogQueries = comparable.Filter(structuredLogQueries, func(q string) bool {
+		return q != ""
+	})
+	if len(structuredLogQueries) > 0 {
+		query = append(query, "("+strings.Join(structuredLogQueries, " OR ")+")")
+	}
+	if opts.errorMessage != "" {
+		query = append(query, opts.errorMessage+" ")
+	}
+	return query
+}
+
+func withStatus(query []string, status []string) []string {
+	if len(status) == 0 {
+		return query
+	}
+	expression := strings.Join(status, " OR ")
+	return append(query, fmt.Sprintf("status:(%v)", expression))
+}
+
+func buildLogsQuery(opts queryOptions) []string {
+	return withStatus(buildQuery(opts), opts.logStatus)
+}
+
+func buildTracesQuery(opts queryOptions) []string {
+	return withStatus(buildQuery(opts), opts.traceStatus)
+}
+
+func buildLogsURL(opts queryOptions) string {
+	queryUrlParam := getQueryUrlParam(buildLogsQuery(opts))
+	timeRangeUrlParam := ""
+	liveTail := ""
+	if opts.timeRange == "live" {
+		liveTail = "/livetail"
+	} else {
+		timeRangeUrlParam = fmt.Sprintf("from_ts=%v&to_ts=%v&", opts.start, opts.end)
+	}
+	return fmt.Sprintf("https://%v.datadoghq.com/logs%v?%v%v", opts.datadogInstance, liveTail, timeRangeUrlParam, queryUrlParam)
+}
+
+func buildTracesURL(opts queryOptions) string {
+	queryUrlParam := getQueryUrlParam(buildTracesQuery(opts))
+	timeRangeUrlParam := ""
+	historicalData := true
+	if opts.timeRange == "live" {
+		historicalData = false
+	} else {
+		timeRangeUrlParam = fmt.Sprintf("start=%v&end=%v&", opts.start, opts.end)
+	}
+	return fmt.Sprintf("https://%v.datadoghq.com/apm/traces?%v%vhistoricalData=%v", opts.datadogInstance, timeRangeUrlParam, queryUrlParam, historicalData)
+}
@@ -0,0 +1,98 @@
+package datadog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"toolbelt/pkg/cli"
+)
+
+func TestBuildURLs(t *testing.T) {
+	const query = "service%3A%28metricflow-server%29+status%3A%28error%29"
+	live := queryOptions{
+		services:        []string{"metricflow-server"},
+		datadogInstance: "dbtlabsmt",
+		timeRange:       "live",
+		logStatus:       []string{"error"},
+		traceStatus:     []string{"error"},
+	}
+	historical := live
+	historical.timeRange = "1-hour"
+	historical.start = 1000
+	historical.end = 2000
+	empty := queryOptions{datadogInstance: "dbtlabsstaws", timeRange: "live"}
+
+	tests := []struct {
+		name  string
+		build func(queryOptions) string
+		opts  queryOptions
+		want  string
+	}{
+		{"logs live", buildLogsURL, live, "https://dbtlabsmt.datadoghq.com/logs/livetail?query=" + query + "&"},
+		{"logs historical", buildLogsURL, historical, "https://dbtlabsmt.datadoghq.com/logs?from_ts=1000&to_ts=2000&query=" + query + "&"},
+		{"logs empty query", buildLogsURL, empty, "https://dbtlabsstaws.datadoghq.com/logs/livetail?"},
+		{"traces live", buildTracesURL, live, "https://dbtlabsmt.datadoghq.com/apm/traces?query=" + query + "&historicalData=false"},
+		{"traces historical", buildTracesURL, historical, "https://dbtlabsmt.datadoghq.com/apm/traces?start=1000&end=2000&query=" + query + "&historicalData=true"},
+		{"traces empty query", buildTracesURL, empty, "https://dbtlabsstaws.datadoghq.com/apm/traces?historicalData=false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.build(tt.opts); got != tt.want {
+				t.Errorf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildQueryStructuredLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		want     []string
+	}{
+		{
+			"known and unknown services",
+			[]string{"metricflow-server", "elb", "semantic-layer-gateway", "unknown"},
+			[]string{
+				"service:(metricflow-server OR elb OR semantic-layer-gateway OR unknown)",
+				"((@extra.environment_id:1 OR @extra.environment_id:2) OR (@environment_id:1 OR @environment_id:2))",
+			},
+		},
+		{
+			"only unknown services",
+			[]string{"elb", "unknown"},
+			[]string{"service:(elb OR unknown)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildQuery(queryOptions{services: tt.services, envId: "1, 2"})
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if joined := strings.Join(got, " "); strings.Contains(joined, "OR  OR") || strings.Contains(joined, "()") {
+				t.Errorf("%q has an empty clause", joined)
+			}
+		})
+	}
+}
+
+func TestErrorsTimeRange(t *testing.T) {
+	opts, err := errorsOptions([]string{"--range", "live"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.timeRange != "live" || opts.start != 0 || opts.end != 0 {
+		t.Errorf("live got %+v, want no timestamps", opts)
+	}
+	opts, err = errorsOptions([]string{"--range", "1-hour"})
+	if err != nil || opts.end-opts.start != time.Hour.Milliseconds() {
+		t.Errorf("1-hour got %+v, %v", opts, err)
+	}
+	for _, r := range []string{"1h", "hour", ""} {
+		if _, err := errorsOptions([]string{"--range", r}); !errors.Is(err, cli.ErrUsage) {
+			t.Errorf("--range %q got %v, want a usage error", r, err)
+		}
+	}
+}
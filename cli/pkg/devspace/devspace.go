@@ -0,0 +1,50 @@
+package devspace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/shell"
+)
+
+const deleteInterval = 2 * time.Second
+const deleteTimeout = 3 * time.Minute
+
+// namespaceGone reports whether the namespace has finished deleting. With
+// --ignore-not-found, kubectl prints nothing once it's gone.
+func namespaceGone(namespace string) (bool, error) {
+	c := shell.New("kubectl get namespace %v --ignore-not-found -o name", namespace).WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) == "", nil
+}
+
+// Reset deletes the devspace namespace, waits for the deletion to finish,
+// and recreates it.
+func Reset(params []string) error {
+	err := shell.Require("kubectl", "devspace")
+	if err != nil {
+		return err
+	}
+	namespace := config.DEVSPACE_NAMESPACE
+	c := shell.New("kubectl delete namespace %v --ignore-not-found --wait=false", namespace)
+	_, err = c.RunCmd()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("waiting for %v to be deleted\n", namespace)
+	err = shell.Poll(func() (bool, error) {
+		return namespaceGone(namespace)
+	}, deleteInterval, deleteTimeout)
+	if err != nil {
+		return fmt.Errorf("namespace %v is still being deleted: %v", namespace, err)
+	}
+	_, err = shell.RunCmds([]shell.Cmd{
+		shell.New("kubectl create namespace %v", namespace),
+		shell.New("devspace use namespace %v", namespace),
+	})
+	return err
+}
@@ -0,0 +1,38 @@
+package devspace
+
+import (
+	"reflect"
+	"testing"
+	"toolbelt/pkg/shell/shelltest"
+)
+
+func TestHelperProcess(t *testing.T) {
+	shelltest.HelperProcess()
+}
+
+func TestNamespaceGone(t *testing.T) {
+	tests := []struct {
+		out  string
+		want bool
+	}{
+		{"", true},
+		{"namespace/dev\n", false},
+	}
+	for _, tt := range tests {
+		fake := shelltest.Install(t)
+		fake.Stub(tt.out, "", 0)
+		gone, err := namespaceGone("dev")
+		if err != nil || gone != tt.want {
+			t.Errorf("output %q: got %v, %v, want %v", tt.out, gone, err, tt.want)
+		}
+		want := []string{"kubectl", "get", "namespace", "dev", "--ignore-not-found", "-o", "name"}
+		if calls := fake.Calls(); len(calls) != 1 || !reflect.DeepEqual(calls[0], want) {
+			t.Errorf("got calls %q, want %q", calls, want)
+		}
+	}
+	fake := shelltest.Install(t)
+	fake.Stub("", "Unauthorized", 1)
+	if _, err := namespaceGone("dev"); err == nil {
+		t.Error("expected kubectl failures to be returned")
+	}
+}
@@ -0,0 +1,93 @@
+package doctor
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+type tool struct {
+	name        string
+	required    bool
+	versionArgs []string
+}
+
+var tools = []tool{
+	{"git", true, []string{"--version"}},
+	{"go", true, []string{"version"}},
+	{"gh", false, []string{"--version"}},
+	{"aws", false, []string{"--version"}},
+	{"devspace", false, []string{"--version"}},
+	{"code", false, []string{"--version"}},
+	{"lsof", false, []string{"-v"}},
+	{"brew", false, []string{"--version"}},
+}
+
+type Result struct {
+	Tool     string `json:"tool"`
+	Found    bool   `json:"found"`
+	Version  string `json:"version,omitempty"`
+	Required bool   `json:"required"`
+}
+
+func check(t tool) Result {
+	result := Result{Tool: t.name, Required: t.required}
+	if _, ok := shell.Which(t.name); !ok {
+		return result
+	}
+	result.Found = true
+	out, _ := exec.Command(t.name, t.versionArgs...).CombinedOutput()
+	result.Version = strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	return result
+}
+
+func Run(params []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	format := flags.String("format", "table", "table or json")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	results := []Result{}
+	missing := []string{}
+	for _, t := range tools {
+		result := check(t)
+		if result.Required && !result.Found {
+			missing = append(missing, t.name)
+		}
+		results = append(results, result)
+	}
+	switch *format {
+	case "json":
+		bytes, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+	case "table":
+		t := table.New("TOOL", "FOUND", "REQUIRED", "VERSION").WithColor()
+		for _, r := range results {
+			t.AddRow(r.Tool, r.Found, r.Required, r.Version)
+		}
+		err = t.Render(os.Stdout)
+		if err != nil {
+			return err
+		}
+	default:
+		return cli.Usagef("unknown format %v. use table or json", *format)
+	}
+	if len(missing) > 0 {
+		hints := []string{}
+		for _, name := range missing {
+			hints = append(hints, shell.MissingError{Tool: name}.Error())
+		}
+		return cli.MissingDependencyf("missing required tools:\n%v", strings.Join(hints, "\n"))
+	}
+	return nil
+}
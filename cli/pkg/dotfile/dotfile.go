@@ -0,0 +1,46 @@
+package dotfile
+
+import (
+	"fmt"
+	"path"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/vscode"
+)
+
+// sync copies src to dest and prints each file it wrote.
+func sync(src string, dest string) error {
+	copied, err := fs.Copy(src, dest)
+	for _, c := range copied {
+		fmt.Printf("synced %v -> %v\n", c.Src, c.Dest)
+	}
+	return err
+}
+
+func Pull(params []string) error {
+	err := pullSettings()
+	if err != nil {
+		return err
+	}
+	for _, d := range config.DOTFILES {
+		err = sync(path.Join(config.DOTFILES_PATH, d.Src), d.Dest)
+		if err != nil {
+			return err
+		}
+	}
+	return vscode.PullExtensions()
+}
+
+func Push(params []string) error {
+	err := sync(vscode.SettingsPath(), config.VSCODE_DOTFILES_SETTINGS)
+	if err != nil {
+		return err
+	}
+	for _, d := range config.DOTFILES {
+		err = sync(d.Dest, path.Join(config.DOTFILES_PATH, d.Src))
+		if err != nil {
+			return err
+		}
+	}
+	return vscode.PushExtensions()
+}
@@ -0,0 +1,56 @@
+package dotfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/tty"
+	"toolbelt/pkg/vscode"
+)
+
+// pullSettings copies the dotfiles repo's settings over the local ones,
+// backing up and confirming first when that would discard local edits.
+func pullSettings() error {
+	local := vscode.SettingsPath()
+	incoming := config.VSCODE_DOTFILES_SETTINGS
+	current, err := os.ReadFile(local)
+	if os.IsNotExist(err) {
+		return sync(incoming, local)
+	}
+	if err != nil {
+		return err
+	}
+	next, err := os.ReadFile(incoming)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, next) {
+		return nil
+	}
+	if tty.IsInteractive() {
+		// git diff exits non-zero when the files differ.
+		diff := shell.NewFromArray([]string{"git", "--no-pager", "diff", "--no-index", "--", local, incoming})
+		diff.RunAttached()
+	}
+	// Without a terminal the local settings are kept unless -y is passed.
+	confirmed, err := prompt.Confirm("Overwrite local VS Code settings?", false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Printf("kept %v\n", local)
+		return nil
+	}
+	backup := fmt.Sprintf("%v.%v.bak", local, time.Now().Format("20060102-150405"))
+	err = fs.CopyFile(local, backup)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backed up %v to %v\n", local, backup)
+	return fs.CopyFile(incoming, local)
+}
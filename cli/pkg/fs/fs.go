@@ -1,19 +1,109 @@
 package fs
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+)
+
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
 
 func CopyFile(src string, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
 	bytes, err := os.ReadFile(src)
 	if err != nil {
 		return err
 	}
 	err = os.Remove(dest)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(dest), 0755)
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(dest, bytes, 0777)
+	err = os.WriteFile(dest, bytes, info.Mode().Perm())
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Copied is a file written by Copy or CopyDir.
+type Copied struct {
+	Src  string
+	Dest string
+}
+
+// CopyDir recursively copies the tree at src to dest, preserving file modes,
+// and returns the files it wrote. Symlinks are followed, but a directory is
+// never copied twice so symlink loops are skipped.
+func CopyDir(src string, dest string) ([]Copied, error) {
+	copied := []Copied{}
+	err := copyDir(src, dest, map[string]bool{}, &copied)
+	return copied, err
+}
+
+func copyDir(src string, dest string, visited map[string]bool, copied *[]Copied) error {
+	real, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	if visited[real] {
+		return nil
+	}
+	visited[real] = true
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dest, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from := filepath.Join(src, entry.Name())
+		to := filepath.Join(dest, entry.Name())
+		info, err := os.Stat(from)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = copyDir(from, to, visited, copied)
+		} else {
+			err = CopyFile(from, to)
+			if err == nil {
+				*copied = append(*copied, Copied{Src: from, Dest: to})
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy copies a file or a directory tree and returns the files it wrote.
+func Copy(src string, dest string) ([]Copied, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return CopyDir(src, dest)
+	}
+	err = CopyFile(src, dest)
+	if err != nil {
+		return nil, err
+	}
+	return []Copied{{Src: src, Dest: dest}}, nil
+}
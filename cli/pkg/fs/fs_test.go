@@ -0,0 +1,94 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func write(t *testing.T, p string, content string, mode os.FileMode) {
+	t.Helper()
+	err := os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(p, []byte(content), mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile's mode is masked by the umask
+	err = os.Chmod(p, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	dir := t.TempDir()
+	if !Exists(dir) {
+		t.Errorf("%v should exist", dir)
+	}
+	if Exists(filepath.Join(dir, "missing")) {
+		t.Error("missing file shouldn't exist")
+	}
+}
+
+func TestCopyDirNested(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dest := filepath.Join(t.TempDir(), "dest")
+	write(t, filepath.Join(src, "top.txt"), "top", 0644)
+	write(t, filepath.Join(src, "a", "b", "deep.txt"), "deep", 0644)
+	_, err := CopyDir(src, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rel, want := range map[string]string{"top.txt": "top", "a/b/deep.txt": "deep"} {
+		got, err := os.ReadFile(filepath.Join(dest, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%v has %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestCopyDirPreservesMode(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dest := filepath.Join(t.TempDir(), "dest")
+	write(t, filepath.Join(src, "script.sh"), "#!/bin/sh", 0755)
+	write(t, filepath.Join(src, "secret"), "shh", 0600)
+	_, err := CopyDir(src, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]os.FileMode{"script.sh": 0755, "secret": 0600} {
+		info, err := os.Stat(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%v has mode %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCopyDirSkipsSymlinkLoop(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dest := filepath.Join(t.TempDir(), "dest")
+	write(t, filepath.Join(src, "sub", "file.txt"), "x", 0644)
+	err := os.Symlink(src, filepath.Join(src, "sub", "loop"))
+	if err != nil {
+		t.Skipf("can't create symlinks: %v", err)
+	}
+	_, err = CopyDir(src, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Exists(filepath.Join(dest, "sub", "file.txt")) {
+		t.Error("expected sub/file.txt to be copied")
+	}
+	if Exists(filepath.Join(dest, "sub", "loop", "sub")) {
+		t.Error("the symlink loop was followed")
+	}
+}
@@ -0,0 +1,59 @@
+package fs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+type FileInfo struct {
+	Size int64
+	SHA  string
+}
+
+func Stat(file string) (FileInfo, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Size: size, SHA: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// ReplaceFile copies src to a temp file next to dest and renames it into
+// place, so dest is never left half-written even if dest is the running
+// executable.
+func ReplaceFile(src string, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, in)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), mode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
@@ -0,0 +1,138 @@
+package gh
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"toolbelt/pkg/cache"
+	"toolbelt/pkg/shell"
+)
+
+type PR struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	State  string `json:"state"`
+}
+
+type Run struct {
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HeadBranch string `json:"headBranch"`
+}
+
+type CreatePROptions struct {
+	Title string
+	Body  string
+	// Base defaults to the repo's default branch when empty.
+	Base  string
+	Draft bool
+}
+
+func Available() bool {
+	_, ok := shell.Which("gh")
+	return ok
+}
+
+// run runs gh with args in dir and returns its stdout, turning a missing or
+// unauthenticated gh into a clear error.
+func run(dir string, args ...string) (string, error) {
+	if err := shell.Require("gh"); err != nil {
+		return "", err
+	}
+	c := shell.NewFromArray(append([]string{"gh"}, args...))
+	if dir != "" {
+		c = shell.NewFromArrayWithDir(dir, append([]string{"gh"}, args...))
+	}
+	c = c.WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		if strings.Contains(err.Error(), "gh auth login") {
+			return "", fmt.Errorf("gh isn't authenticated. run `gh auth login`")
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// CurrentPR returns the open pull request for the branch checked out in dir,
+// or nil if there isn't one.
+func CurrentPR(dir string) (*PR, error) {
+	out, err := run(dir, "pr", "view", "--json", "number,title,url,state")
+	if err != nil {
+		if strings.Contains(err.Error(), "no pull requests found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pr PR
+	err = json.Unmarshal([]byte(out), &pr)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse gh pr view output: %v", err)
+	}
+	if pr.State != "OPEN" {
+		return nil, nil
+	}
+	return &pr, nil
+}
+
+// ListRuns returns the most recent workflow runs for branch, newest first.
+func ListRuns(dir string, branch string, limit int) ([]Run, error) {
+	out, err := run(dir, "run", "list", "--branch", branch, "--limit", strconv.Itoa(limit), "--json", "url,status,conclusion,headBranch")
+	if err != nil {
+		return nil, err
+	}
+	runs := []Run{}
+	err = json.Unmarshal([]byte(out), &runs)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse gh run list output: %v", err)
+	}
+	return runs, nil
+}
+
+// CreatePR opens a pull request for the branch checked out in dir and
+// returns its URL.
+func CreatePR(dir string, opts CreatePROptions) (string, error) {
+	args := []string{"pr", "create", "--title", opts.Title, "--body", opts.Body}
+	if opts.Base != "" {
+		args = append(args, "--base", opts.Base)
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	out, err := run(dir, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CommentPR posts body as a comment on the pull request at url.
+func CommentPR(dir string, url string, body string) error {
+	_, err := run(dir, "pr", "comment", url, "--body", body)
+	return err
+}
+
+func AddReviewers(dir string, reviewers []string) error {
+	_, err := run(dir, "pr", "edit", "--add-reviewer", strings.Join(reviewers, ","))
+	return err
+}
+
+const reviewRequestTTL = 10 * time.Minute
+
+// ReviewRequestCount returns how many open pull requests are waiting on
+// user's review.
+func ReviewRequestCount(user string) (int, error) {
+	out, err := cache.Lookup("review-requests:"+user, reviewRequestTTL, func() (string, error) {
+		return run("", "api", "-X", "GET", "search/issues",
+			"-f", "q=is:pr is:open review-requested:"+user,
+			"--jq", ".total_count")
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
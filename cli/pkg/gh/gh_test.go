@@ -0,0 +1,32 @@
+package gh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"toolbelt/pkg/shell/shelltest"
+)
+
+func TestHelperProcess(t *testing.T) {
+	shelltest.HelperProcess()
+}
+
+func TestCommentPR(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "gh"), []byte("#!/bin/sh\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	fake := shelltest.Install(t)
+	url := "https://github.com/org/repo/pull/1"
+	err = CommentPR(dir, url, "Addressed review feedback in abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"gh", "pr", "comment", url, "--body", "Addressed review feedback in abc123"}
+	if calls := fake.Calls(); len(calls) != 1 || !reflect.DeepEqual(calls[0], want) {
+		t.Errorf("got calls %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/shell"
+)
+
+// parseLocation splits "file:line", defaulting the line to 1.
+func parseLocation(location string) (string, int, error) {
+	i := strings.LastIndex(location, ":")
+	if i == -1 {
+		return location, 1, nil
+	}
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("invalid line in %v", location)
+	}
+	return location[:i], line, nil
+}
+
+func BlameOpen(params []string) error {
+	file, line, err := parseLocation(params[0])
+	if err != nil {
+		return err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	remote, err := Remote(dir)
+	if err != nil {
+		return err
+	}
+	c := shell.NewFromArrayWithDir(dir, []string{"git", "ls-files", "--full-name", "--", file}).WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	tracked := strings.TrimSpace(out)
+	if tracked == "" || strings.Contains(tracked, "\n") {
+		return fmt.Errorf("%v is not a file tracked in this repo", file)
+	}
+	c = shell.NewWithDir(dir, "git rev-parse HEAD").WithQuiet()
+	out, err = c.RunCmd()
+	if err != nil {
+		return err
+	}
+	sha := strings.TrimSpace(out)
+	return browser.Open(fmt.Sprintf("%v/blame/%v/%v#L%v", remote.URL(), sha, tracked, line))
+}
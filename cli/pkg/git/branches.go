@@ -0,0 +1,116 @@
+package git
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"toolbelt/pkg/cache"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+)
+
+const defaultBranchTTL = 24 * time.Hour
+
+func DefaultBranch(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return cache.Lookup("default-branch:"+abs, defaultBranchTTL, func() (string, error) {
+		return defaultBranch(dir)
+	})
+}
+
+func defaultBranch(dir string) (string, error) {
+	c := shell.NewWithDir(dir, "git symbolic-ref --short refs/remotes/origin/HEAD").WithQuiet()
+	out, err := c.RunCmd()
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(out), "origin/"), nil
+	}
+	c = shell.NewWithDir(dir, "git remote show origin").WithQuiet()
+	out, err = c.RunCmd()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "HEAD branch:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "HEAD branch:")), nil
+		}
+	}
+	return "", fmt.Errorf("couldn't determine the default branch of %v", dir)
+}
+
+func localBranches(dir string, args ...string) ([]string, error) {
+	cmd := append([]string{"git", "branch", "--format=%(refname:short)"}, args...)
+	c := shell.NewFromArrayWithDir(dir, cmd).WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil, err
+	}
+	branches := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+func CleanBranches(params []string) error {
+	flags := flag.NewFlagSet("git clean-branches", flag.ContinueOnError)
+	force := flags.Bool("force", false, "also offer unmerged branches and delete with -D")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defaultBranch, err := DefaultBranch(dir)
+	if err != nil {
+		return err
+	}
+	current, _ := CurrentBranch(dir)
+	merged, err := localBranches(dir, "--merged", defaultBranch)
+	if err != nil {
+		return err
+	}
+	candidates := merged
+	if *force {
+		candidates, err = localBranches(dir)
+		if err != nil {
+			return err
+		}
+	}
+	candidates = comparable.Subtract(candidates, []string{defaultBranch, current})
+	if len(candidates) == 0 {
+		fmt.Println("no branches to clean")
+		return nil
+	}
+	options := []prompt.Option{}
+	for _, branch := range candidates {
+		// merged branches are checked up front
+		options = append(options, prompt.Option{Label: branch, Value: branch, Selected: comparable.Includes(merged, branch)})
+	}
+	selected, err := prompt.MultiSelectOptions("Branches to delete", options)
+	if err != nil {
+		return err
+	}
+	deleteFlag := "-d"
+	if *force {
+		deleteFlag = "-D"
+	}
+	cmds := []shell.Cmd{}
+	for _, branch := range selected {
+		cmds = append(cmds, shell.NewFromArray([]string{"git", "branch", deleteFlag, branch}))
+	}
+	_, err = shell.RunCmdsInDir(dir, cmds)
+	return err
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"fmt"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+)
+
+// maxFixupIterations is how many times fixupLint restages before giving up.
+const maxFixupIterations = 3
+
+// preSave runs the pre-save steps of the repo dir belongs to, like a
+// formatter, from the repo root.
+func preSave(dir string) error {
+	r := repo.In(dir)
+	if r == nil {
+		return nil
+	}
+	cmds := r.PreSave()
+	if len(cmds) == 0 {
+		return nil
+	}
+	root, err := repo.Root(dir)
+	if err != nil {
+		return err
+	}
+	_, err = shell.RunCmdsInDir(root, cmds)
+	return err
+}
+
+// fixupLint runs the formatter of the repo dir belongs to and restages
+// until the formatter stops changing files, so the commit passes lint hooks.
+func fixupLint(dir string) error {
+	r := repo.In(dir)
+	if r == nil {
+		return fmt.Errorf("--fixup-lint needs a recognized repo")
+	}
+	opts, err := repo.OptionsIn(dir, ".env")
+	if err != nil {
+		return err
+	}
+	for i := 0; ; i++ {
+		err := r.Format(opts)
+		if err != nil {
+			return err
+		}
+		// diff --quiet exits non-zero when the formatter left unstaged changes.
+		diff := shell.NewWithDir(opts.Dir, "git diff --quiet").WithQuiet()
+		if _, err := diff.RunCmd(); err == nil {
+			return nil
+		}
+		if i == maxFixupIterations {
+			return fmt.Errorf("formatter still changing files after %v runs", i+1)
+		}
+		// the formatter only rewrites tracked files
+		add := shell.NewWithDir(opts.Dir, "git add -u")
+		_, err = add.RunCmd()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// testBeforePush runs the tests of the repo dir belongs to like `dev test`
+// does, leaving the commit local if they fail so it can be fixed and amended.
+func testBeforePush(dir string) error {
+	r := repo.In(dir)
+	if r == nil {
+		return fmt.Errorf("--test needs a recognized repo")
+	}
+	opts, err := repo.OptionsIn(dir, ".env", ".env.test")
+	if err != nil {
+		return err
+	}
+	err = r.Test(opts)
+	if err != nil {
+		return fmt.Errorf("tests failed, so the commit wasn't pushed. fix it and amend: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/gh"
+)
+
+func OpenCI() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	remote, err := Remote(dir)
+	if err != nil {
+		return err
+	}
+	actionsUrl := remote.URL() + "/actions"
+	branch, err := CurrentBranch(dir)
+	if err != nil {
+		fmt.Printf("%v. opening the Actions page instead\n", err)
+		return browser.Open(actionsUrl)
+	}
+	if !gh.Available() {
+		fmt.Println("gh isn't installed. opening the Actions page instead")
+		return browser.Open(actionsUrl)
+	}
+	runs, err := gh.ListRuns(dir, branch, 1)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("no CI runs found for %v. opening the Actions page instead\n", branch)
+		return browser.Open(actionsUrl)
+	}
+	return browser.Open(runs[0].URL)
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"fmt"
+	"toolbelt/pkg/prompt"
+)
+
+var commitTypes = []string{"feat", "fix", "chore", "docs", "refactor", "test"}
+
+// conventionalMessage asks for a commit type, optional scope, and subject and
+// composes them as "type(scope): subject".
+func conventionalMessage() (string, error) {
+	commitType, err := prompt.Select("Type", commitTypes)
+	if err != nil {
+		return "", err
+	}
+	scope, err := prompt.Input("Scope (optional)")
+	if err != nil {
+		return "", err
+	}
+	subject, err := prompt.Input("Subject")
+	if err != nil {
+		return "", err
+	}
+	if subject == "" {
+		return "", fmt.Errorf("a subject is required")
+	}
+	if scope == "" {
+		return fmt.Sprintf("%v: %v", commitType, subject), nil
+	}
+	return fmt.Sprintf("%v(%v): %v", commitType, scope, subject), nil
+}
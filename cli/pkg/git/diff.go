@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/tty"
+)
+
+func colorizeDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "),
+			strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = colorize("1", line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = colorize("36", line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = colorize("32", line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = colorize("31", line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff shows staged and unstaged changes, rendered with delta when it's
+// installed and colored by hand otherwise. Without a terminal it's plain
+// `git diff`, so the output can be piped to `git apply`.
+func Diff(params []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if !tty.IsInteractive() {
+		c := shell.NewWithDir(dir, "git --no-pager diff")
+		return c.RunAttached()
+	}
+	sections := []struct {
+		title string
+		args  string
+	}{
+		{"staged", "--cached"},
+		{"unstaged", ""},
+	}
+	_, deltaErr := exec.LookPath("delta")
+	for _, section := range sections {
+		c := shell.NewWithDir(dir, "git --no-pager diff "+section.args).WithQuiet()
+		out, err := c.RunCmd()
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(out) == "" {
+			continue
+		}
+		fmt.Printf("%v:\n", section.title)
+		if deltaErr == nil {
+			// delta reads the diff from a pipe, which needs a shell.
+			c := shell.NewShellWithDir(dir, "git --no-pager diff --color=always "+section.args+" | delta")
+			err = c.RunAttached()
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Print(colorizeDiff(out))
+		}
+	}
+	return nil
+}
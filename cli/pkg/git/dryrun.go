@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"toolbelt/pkg/shell"
+)
+
+// pushTarget describes where a plain `git push` from dir would go.
+func pushTarget(dir string) (string, error) {
+	c := shell.NewWithDir(dir, "git rev-parse --abbrev-ref --symbolic-full-name @{upstream}").WithQuiet()
+	out, err := c.RunCmd()
+	if err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	branch, err := CurrentBranch(dir)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("nowhere, %v has no upstream", branch), nil
+}
+
+func dryRunSave(dir string, args []string, edit bool, all bool, split bool, conventional bool) error {
+	c := shell.NewWithDir(dir, "git status --porcelain").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	staged := []string{}
+	files := []string{}
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if line == "" || (!all && strings.HasPrefix(line, "??")) {
+			continue
+		}
+		staged = append(staged, line)
+		// renames are "old -> new", and both sides are part of the commit
+		files = append(files, strings.Split(line[3:], " -> ")...)
+	}
+	if len(staged) == 0 {
+		fmt.Println("nothing to commit")
+	} else {
+		fmt.Println("would stage:")
+		for _, line := range staged {
+			fmt.Printf("  %v\n", line)
+		}
+	}
+	switch {
+	case split:
+		groups := byTopDir(files)
+		tops := []string{}
+		for top := range groups {
+			tops = append(tops, top)
+		}
+		sort.Strings(tops)
+		fmt.Println("would commit:")
+		for _, top := range tops {
+			message, err := withTicket(dir, "update "+top)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  %v (%v files)\n", message, len(groups[top]))
+		}
+	case edit:
+		fmt.Println("message: written in an editor")
+	case conventional:
+		fmt.Println("message: composed from a conventional commit type, scope, and subject")
+	case len(args) > 0:
+		message, err := withTicket(dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("message: %v\n", message)
+	default:
+		if url, ok := openPR(dir); ok {
+			fmt.Printf("message: %v (%v)\n", reviewMessage, url)
+		} else {
+			fmt.Println("message: none. a message or -e is required")
+		}
+	}
+	target, err := pushTarget(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("would push to: %v\n", target)
+	return nil
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"toolbelt/pkg/shell"
+)
+
+func StagedStat(dir string) (string, error) {
+	c := shell.NewWithDir(dir, "git diff --cached --stat").WithQuiet()
+	return c.RunCmd()
+}
+
+func editor(dir string) string {
+	c := shell.NewWithDir(dir, "git var GIT_EDITOR").WithQuiet()
+	out, err := c.RunCmd()
+	if err == nil && strings.TrimSpace(out) != "" {
+		return strings.TrimSpace(out)
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	return "vi"
+}
+
+// editMessage opens the user's editor on a template listing the staged
+// changes and returns the message with comment lines removed.
+func editMessage(dir string) (string, error) {
+	stat, err := StagedStat(dir)
+	if err != nil {
+		return "", err
+	}
+	template := "\n# Enter the commit message. Lines starting with # are ignored and an\n# empty message aborts the save.\n#\n"
+	for _, line := range strings.Split(strings.TrimRight(stat, "\n"), "\n") {
+		template += "# " + line + "\n"
+	}
+	f, err := os.CreateTemp("", "toolbelt-commit-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(template)
+	f.Close()
+	if err != nil {
+		return "", err
+	}
+	cmd := shell.NewWithDir(dir, editor(dir)+" "+f.Name())
+	err = cmd.RunAttached()
+	if err != nil {
+		return "", err
+	}
+	bytes, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	lines := []string{}
+	for _, line := range strings.Split(string(bytes), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	message := strings.TrimSpace(strings.Join(lines, "\n"))
+	if message == "" {
+		return "", fmt.Errorf("empty commit message, aborting the save")
+	}
+	return message, nil
+}
@@ -1,18 +1,208 @@
 package git
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/clipboard"
+	"toolbelt/pkg/gh"
 	"toolbelt/pkg/shell"
 )
 
 func Save(params []string) error {
-	dir, _ := os.Getwd()
-	_, err := shell.RunCmdsFromStr(
-		dir,
-		"git add -A",
-		fmt.Sprintf("git commit -m \"%s\"", params[0]),
-		"git push",
-	)
+	flags := flag.NewFlagSet("git save", flag.ContinueOnError)
+	noVerify := flags.Bool("no-verify", false, "skip pre-commit and pre-push hooks")
+	edit := flags.Bool("e", false, "write the commit message in an editor")
+	fixup := flags.Bool("fixup-lint", false, "run the repo's formatter and restage until it makes no changes")
+	cwd, _ := os.Getwd()
+	dirFlag := flags.String("dir", cwd, "the repo to save")
+	dryRun := flags.Bool("dry-run", false, "print what would be committed and pushed without doing it")
+	test := flags.Bool("test", false, "run the repo's tests after committing and only push if they pass")
+	split := flags.Bool("split", false, "make one commit per top-level directory")
+	all := flags.Bool("all", config.SAVE_ADD_ALL, "stage untracked files too, not just changes to tracked ones")
+	copyURL := flags.Bool("copy", false, "copy the pushed commit's URL to the clipboard")
+	openURL := flags.Bool("open", false, "open the pushed commit in the browser")
+	conventional := flags.Bool("conventional", false, "compose a conventional commit message from a type, scope, and subject")
+	comment := flags.Bool("comment", false, "with no message on a branch with an open PR, the message is \""+reviewMessage+"\". this also comments on the PR once pushed")
+	args, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if *conventional && len(args) > 0 {
+		return cli.Usagef("pass a commit message or --conventional, not both")
+	}
+	dir := config.ExpandHome(*dirFlag)
+	if *dryRun {
+		return dryRunSave(dir, args, *edit, *all, *split, *conventional)
+	}
+	// Find the message before anything is staged or formatted, so a missing
+	// one fails without side effects. -e waits for the staged stat.
+	var message, prUrl string
+	if !*split && !*edit {
+		message, prUrl, err = commitMessage(dir, args, *conventional)
+		if err != nil {
+			return err
+		}
+	}
+	err = preSave(dir)
+	if err != nil {
+		return err
+	}
+	add := shell.NewWithDir(dir, "git add -u")
+	if *all {
+		add = shell.NewWithDir(dir, "git add -A")
+	}
+	_, err = add.RunCmd()
+	if err != nil {
+		return err
+	}
+	if *fixup {
+		err = fixupLint(dir)
+		if err != nil {
+			return err
+		}
+	}
+	err = confirmLargeFiles(dir)
+	if err != nil {
+		return err
+	}
+	if *split {
+		err = commitSplit(dir, *noVerify)
+	} else {
+		err = commitStaged(dir, message, *edit, *noVerify)
+	}
+	if err != nil {
+		return err
+	}
+	if *test {
+		err = testBeforePush(dir)
+		if err != nil {
+			return err
+		}
+	}
+	push := []string{"git", "push"}
+	if *noVerify {
+		push = append(push, "--no-verify")
+	}
+	c := shell.NewFromArrayWithDir(dir, push)
+	_, err = c.RunCmd()
+	if err != nil {
+		return err
+	}
+	if prUrl != "" {
+		fmt.Printf("updated %v\n", prUrl)
+		if *comment {
+			err = commentPushed(dir, prUrl)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return shareCommit(dir, *copyURL, *openURL)
+}
+
+// commentPushed tells the PR's reviewers which commit addressed their
+// feedback.
+func commentPushed(dir string, prUrl string) error {
+	c := shell.NewWithDir(dir, "git rev-parse HEAD").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	err = gh.CommentPR(dir, prUrl, fmt.Sprintf("Addressed review feedback in %v", strings.TrimSpace(out)))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("commented on %v\n", prUrl)
+	return nil
+}
+
+// shareCommit prints the GitHub URL of the pushed commit, optionally copying
+// or opening it. Remotes that aren't GitHub just get the SHA.
+func shareCommit(dir string, copyURL bool, openURL bool) error {
+	c := shell.NewWithDir(dir, "git rev-parse HEAD").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	sha := strings.TrimSpace(out)
+	remote, err := Remote(dir)
+	if err != nil {
+		fmt.Printf("pushed %v\n", sha)
+		return nil
+	}
+	url := remote.CommitURL(sha)
+	fmt.Printf("pushed %v\n", url)
+	if copyURL {
+		err = clipboard.Copy(url)
+		if err != nil {
+			return err
+		}
+	}
+	if openURL {
+		return browser.Open(url)
+	}
+	return nil
+}
+
+const reviewMessage = "address review feedback"
+
+// commitMessage returns the commit message from args, the conventional
+// commit form, or the open PR, and the PR's URL if that was used.
+func commitMessage(dir string, args []string, conventional bool) (string, string, error) {
+	if conventional {
+		message, err := conventionalMessage()
+		return message, "", err
+	}
+	if len(args) > 0 {
+		return args[0], "", nil
+	}
+	if url, ok := openPR(dir); ok {
+		return reviewMessage, url, nil
+	}
+	return "", "", fmt.Errorf("a commit message is required, or pass -e to write one in an editor")
+}
+
+// commitStaged commits everything staged with message, or one written in the
+// editor if edit is set.
+func commitStaged(dir string, message string, edit bool, noVerify bool) error {
+	var err error
+	if edit {
+		message, err = editMessage(dir)
+		if err != nil {
+			return err
+		}
+	}
+	message, err = withTicket(dir, message)
+	if err != nil {
+		return err
+	}
+	return commit(dir, message, noVerify)
+}
+
+func commit(dir string, message string, noVerify bool) error {
+	args := []string{"git", "commit", "-m", message}
+	if noVerify {
+		args = append(args, "--no-verify")
+	}
+	c := shell.NewFromArrayWithDir(dir, args)
+	_, err := c.RunCmd()
 	return err
 }
+
+// openPR returns the URL of the open pull request for the current branch,
+// if there is one.
+func openPR(dir string) (string, bool) {
+	if !gh.Available() {
+		return "", false
+	}
+	pr, err := gh.CurrentPR(dir)
+	if err != nil || pr == nil {
+		return "", false
+	}
+	return pr.URL, true
+}
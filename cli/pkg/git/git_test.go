@@ -0,0 +1,114 @@
+package git
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"testing"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell/shelltest"
+)
+
+func TestHelperProcess(t *testing.T) {
+	shelltest.HelperProcess()
+}
+
+func TestCommitPassesMessageVerbatim(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		noVerify bool
+		want     []string
+	}{
+		{"quotes", `fix "quoted" thing`, false, []string{"git", "commit", "-m", `fix "quoted" thing`}},
+		{"dollar", "cost is $HOME and $(whoami)", false, []string{"git", "commit", "-m", "cost is $HOME and $(whoami)"}},
+		{"no verify", `it's "$x"`, true, []string{"git", "commit", "-m", `it's "$x"`, "--no-verify"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := shelltest.Install(t)
+			err := commit(t.TempDir(), tt.message, tt.noVerify)
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls := fake.Calls()
+			if len(calls) != 1 || !reflect.DeepEqual(calls[0], tt.want) {
+				t.Errorf("got calls %q, want %q", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestConventionalMessage(t *testing.T) {
+	defer func(f func(string, []string) (string, error)) { prompt.SelectFunc = f }(prompt.SelectFunc)
+	defer func(f func(string) (string, error)) { prompt.InputFunc = f }(prompt.InputFunc)
+	prompt.SelectFunc = func(string, []string) (string, error) { return "fix", nil }
+	tests := []struct {
+		scope   string
+		subject string
+		want    string
+	}{
+		{"", " handle empty input ", "fix: handle empty input"},
+		{"shell", "quote args", "fix(shell): quote args"},
+	}
+	for _, tt := range tests {
+		answers := map[string]string{"Scope (optional)": tt.scope, "Subject": tt.subject}
+		prompt.InputFunc = func(msg string) (string, error) { return answers[msg], nil }
+		got, err := conventionalMessage()
+		if err != nil || got != tt.want {
+			t.Errorf("got %q, %v, want %q", got, err, tt.want)
+		}
+	}
+	prompt.InputFunc = func(string) (string, error) { return " ", nil }
+	if _, err := conventionalMessage(); err == nil {
+		t.Error("expected an error for an empty subject")
+	}
+}
+
+func TestSaveRejectsMessageWithConventional(t *testing.T) {
+	fake := shelltest.Install(t)
+	err := Save([]string{"--conventional", "--dir", t.TempDir(), "a message"})
+	if !errors.Is(err, cli.ErrUsage) {
+		t.Errorf("got %v, want a usage error", err)
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("ran %q before rejecting the arguments", calls)
+	}
+}
+
+func TestSaveWithoutMessageHasNoSideEffects(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // no gh, so no open PR to fall back on
+	fake := shelltest.Install(t)
+	err := Save([]string{"--dir", t.TempDir()})
+	if err == nil {
+		t.Error("expected an error without a commit message")
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("ran %q before finding the message was missing", calls)
+	}
+}
+
+func TestPickReposPreselectsLastPick(t *testing.T) {
+	defer func(f func(string, []prompt.Option) ([]string, error)) { prompt.MultiSelectFunc = f }(prompt.MultiSelectFunc)
+	defer func(s, p string) { config.STATE_PATH, lastPickPath = s, p }(config.STATE_PATH, lastPickPath)
+	config.STATE_PATH = t.TempDir()
+	lastPickPath = path.Join(config.STATE_PATH, "pull-pick.json")
+	var asked []prompt.Option
+	prompt.MultiSelectFunc = func(msg string, opts []prompt.Option) ([]string, error) {
+		asked = opts
+		return []string{opts[1].Value}, nil
+	}
+	dirs := []string{"/git/a", "/git/b"}
+	if _, err := pickRepos(dirs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pickRepos(dirs); err != nil {
+		t.Fatal(err)
+	}
+	want := []prompt.Option{{Label: "a", Value: "/git/a"}, {Label: "b", Value: "/git/b", Selected: true}}
+	if !reflect.DeepEqual(asked, want) {
+		t.Errorf("got %+v, want %+v", asked, want)
+	}
+}
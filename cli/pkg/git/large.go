@@ -0,0 +1,81 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+)
+
+func allowedLarge(file string) bool {
+	for _, pattern := range config.LARGE_FILE_ALLOW {
+		if ok, _ := filepath.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// largeStagedFiles returns the staged files over the size threshold, with
+// their sizes, keyed by path relative to the repo root.
+func largeStagedFiles(dir string) (map[string]int64, error) {
+	top, err := repo.Root(dir)
+	if err != nil {
+		return nil, err
+	}
+	c := shell.NewWithDir(dir, "git diff --cached --name-only --diff-filter=AM").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil, err
+	}
+	threshold := int64(config.LARGE_FILE_MB) * 1000 * 1000
+	large := map[string]int64{}
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if file == "" || allowedLarge(file) {
+			continue
+		}
+		info, err := os.Stat(path.Join(top, file))
+		if err != nil || info.Size() <= threshold {
+			continue
+		}
+		large[file] = info.Size()
+	}
+	return large, nil
+}
+
+// confirmLargeFiles asks before committing files over the size threshold,
+// since they stay in history forever.
+func confirmLargeFiles(dir string) error {
+	large, err := largeStagedFiles(dir)
+	if err != nil || len(large) == 0 {
+		return err
+	}
+	fmt.Printf("these staged files are over %vMB:\n", config.LARGE_FILE_MB)
+	files := []string{}
+	for file := range large {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fmt.Printf("  %10v  %v\n", fs.HumanSize(large[file]), file)
+	}
+	confirmed, err := prompt.Confirm("Commit them anyway? large_file_allow skips this check", false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cli.ErrAborted
+	}
+	return nil
+}
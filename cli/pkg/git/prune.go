@@ -0,0 +1,41 @@
+package git
+
+import (
+	"os"
+	"path"
+	"strings"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+// PruneRepos deletes local refs and tags that no longer exist on the remote
+// and prints how many were pruned in each repo.
+func PruneRepos(dirs []string, parallel int) error {
+	cmds := []shell.Cmd{}
+	for _, dir := range dirs {
+		cmds = append(cmds, shell.NewWithDir(dir, "git fetch --prune --prune-tags").WithCombinedOutput())
+	}
+	results := shell.RunCmdsConcurrentN(cmds, parallel)
+	t := table.New("REPO", "PRUNED").WithColor()
+	for i, dir := range dirs {
+		if results[i].Err != nil {
+			t.AddRow(path.Base(dir), "failed")
+			continue
+		}
+		t.AddRow(path.Base(dir), strings.Count(results[i].Out, "[deleted]"))
+	}
+	err := t.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return shell.Failed(results)
+}
+
+func PruneAll(params []string) error {
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	return PruneRepos(dirs, shell.DefaultParallel)
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/tty"
+)
+
+var lastPickPath = path.Join(config.STATE_PATH, "pull-pick.json")
+
+func Pull(params []string) error {
+	flags := flag.NewFlagSet("git pull", flag.ContinueOnError)
+	pick := flags.Bool("pick", false, "choose which repos to pull")
+	prune := flags.Bool("prune", false, "prune deleted remote branches and tags before pulling")
+	parallel := cli.ParallelFlag{N: shell.DefaultParallel}
+	flags.Var(&parallel, "parallel", "how many repos to pull at once. 0 or max for unbounded")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	if *pick && tty.IsInteractive() {
+		dirs, err = pickRepos(dirs)
+		if err != nil {
+			return err
+		}
+	}
+	if *prune {
+		err = PruneRepos(dirs, parallel.N)
+		if err != nil {
+			return err
+		}
+	}
+	return PullRepos(dirs, parallel.N)
+}
+
+func PullRepos(dirs []string, parallel int) error {
+	cmds := []shell.Cmd{}
+	for _, dir := range dirs {
+		cmds = append(cmds, shell.NewWithDir(dir, "git pull"))
+	}
+	results := shell.RunCmdsConcurrentN(cmds, parallel)
+	fmt.Println(pullSummary(results))
+	return shell.Failed(results)
+}
+
+// pullSummary counts how many pulls brought in changes, had none, or failed.
+func pullSummary(results []shell.Result) string {
+	updated, upToDate, failed := 0, 0, 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+		case strings.Contains(r.Out, "Already up to date"):
+			upToDate++
+		default:
+			updated++
+		}
+	}
+	return fmt.Sprintf("pulled %v repos: %v updated, %v up-to-date, %v failed", len(results), updated, upToDate, failed)
+}
+
+func pickRepos(dirs []string) ([]string, error) {
+	last := readLastPick()
+	options := []prompt.Option{}
+	for _, dir := range dirs {
+		options = append(options, prompt.Option{Label: path.Base(dir), Value: dir, Selected: comparable.Includes(last, dir)})
+	}
+	selected, err := prompt.MultiSelectOptions("Repos to pull", options)
+	if err != nil {
+		return nil, err
+	}
+	err = writeLastPick(selected)
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+func readLastPick() []string {
+	selected := []string{}
+	bytes, err := os.ReadFile(lastPickPath)
+	if err != nil {
+		return selected
+	}
+	json.Unmarshal(bytes, &selected)
+	return selected
+}
+
+func writeLastPick(selected []string) error {
+	err := os.MkdirAll(config.STATE_PATH, 0755)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(selected)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lastPickPath, bytes, 0644)
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+type logLine struct {
+	Sha        string
+	Decoration string
+	Subject    string
+}
+
+func parseOneline(out string) []logLine {
+	result := []logLine{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		sha, rest, _ := strings.Cut(line, " ")
+		entry := logLine{Sha: sha, Subject: rest}
+		if strings.HasPrefix(rest, "(") {
+			if end := strings.Index(rest, ") "); end >= 0 {
+				entry.Decoration = rest[:end+1]
+				entry.Subject = rest[end+2:]
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func colorize(code string, text string) string {
+	if os.Getenv("NO_COLOR") != "" || text == "" {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%vm%v\x1b[0m", code, text)
+}
+
+func Recent(params []string) error {
+	flags := flag.NewFlagSet("git recent", flag.ContinueOnError)
+	allRepos := flags.Bool("all-repos", false, "show the last commit of every repo")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if *allRepos {
+		return recentAllRepos()
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	c := shell.NewWithDir(dir, "git log --oneline -n 10 --decorate").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	for _, l := range parseOneline(out) {
+		line := colorize("33", l.Sha)
+		if l.Decoration != "" {
+			line += " " + colorize("36", l.Decoration)
+		}
+		fmt.Println(line + " " + l.Subject)
+	}
+	return nil
+}
+
+func recentAllRepos() error {
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	cmds := []shell.Cmd{}
+	for _, dir := range dirs {
+		cmds = append(cmds, shell.NewWithDir(dir, "git log -1 --oneline --decorate").WithQuiet())
+	}
+	results := shell.RunCmdsConcurrent(cmds)
+	t := table.New("REPO", "COMMIT", "SUBJECT").WithColor()
+	for i, dir := range dirs {
+		lines := parseOneline(results[i].Out)
+		if len(lines) == 0 {
+			t.AddRow(path.Base(dir), "-", "-")
+			continue
+		}
+		t.AddRow(path.Base(dir), lines[0].Sha, strings.TrimSpace(lines[0].Decoration+" "+lines[0].Subject))
+	}
+	err = t.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return shell.Failed(results)
+}
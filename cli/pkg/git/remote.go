@@ -0,0 +1,61 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"toolbelt/pkg/shell"
+)
+
+type GitHubRepo struct {
+	Org  string
+	Name string
+}
+
+func (r GitHubRepo) URL() string {
+	return fmt.Sprintf("https://github.com/%v/%v", r.Org, r.Name)
+}
+
+func (r GitHubRepo) CommitURL(sha string) string {
+	return fmt.Sprintf("%v/commit/%v", r.URL(), sha)
+}
+
+func CurrentBranch(dir string) (string, error) {
+	c := shell.NewWithDir(dir, "git rev-parse --abbrev-ref HEAD").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(out)
+	if branch == "HEAD" {
+		return "", fmt.Errorf("HEAD is detached, check out a branch first")
+	}
+	return branch, nil
+}
+
+func Remote(dir string) (GitHubRepo, error) {
+	c := shell.NewWithDir(dir, "git remote get-url origin").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return GitHubRepo{}, err
+	}
+	return ParseGitHubRemote(strings.TrimSpace(out))
+}
+
+func ParseGitHubRemote(remote string) (GitHubRepo, error) {
+	var rest string
+	switch {
+	case strings.HasPrefix(remote, "git@github.com:"):
+		rest = strings.TrimPrefix(remote, "git@github.com:")
+	case strings.HasPrefix(remote, "ssh://git@github.com/"):
+		rest = strings.TrimPrefix(remote, "ssh://git@github.com/")
+	case strings.HasPrefix(remote, "https://github.com/"):
+		rest = strings.TrimPrefix(remote, "https://github.com/")
+	default:
+		return GitHubRepo{}, fmt.Errorf("%v is not a GitHub remote", remote)
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, ".git"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return GitHubRepo{}, fmt.Errorf("%v is not a GitHub remote", remote)
+	}
+	return GitHubRepo{Org: parts[0], Name: parts[1]}, nil
+}
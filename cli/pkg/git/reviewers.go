@@ -0,0 +1,59 @@
+package git
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/gh"
+	"toolbelt/pkg/repo"
+)
+
+const reviewersPerPR = 2
+
+// leastLoaded orders candidates by how many open reviews they already have.
+// If any lookup fails the candidates are shuffled instead.
+func leastLoaded(candidates []string) []string {
+	counts := map[string]int{}
+	for _, candidate := range candidates {
+		count, err := gh.ReviewRequestCount(candidate)
+		if err != nil {
+			fmt.Printf("couldn't get review load for %v, picking reviewers at random\n", candidate)
+			shuffled := append([]string{}, candidates...)
+			r := rand.New(rand.NewSource(time.Now().UnixNano()))
+			r.Shuffle(len(shuffled), func(i, j int) {
+				shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+			})
+			return shuffled
+		}
+		counts[candidate] = count
+	}
+	sorted := append([]string{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return counts[sorted[i]] < counts[sorted[j]]
+	})
+	return sorted
+}
+
+func RequestReviewers(params []string) error {
+	r := repo.Current()
+	if r == nil {
+		return fmt.Errorf("not in a recognized repo")
+	}
+	candidates := comparable.Subtract(r.Reviewers(), []string{config.GITHUB_USERNAME})
+	if len(candidates) == 0 {
+		return fmt.Errorf("no reviewers configured for this repo")
+	}
+	chosen := leastLoaded(candidates)
+	if len(chosen) > reviewersPerPR {
+		chosen = chosen[:reviewersPerPR]
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	return gh.AddReviewers(dir, chosen)
+}
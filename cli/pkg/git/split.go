@@ -0,0 +1,93 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+)
+
+// stagedByDir buckets the staged paths by their top-level directory, with
+// files at the repo root under ".". Renames are listed as a delete and an
+// add so both sides land in a bucket.
+func stagedByDir(dir string) (map[string][]string, error) {
+	c := shell.NewWithDir(dir, "git diff --cached --name-only --no-renames").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil, err
+	}
+	return byTopDir(strings.Split(strings.TrimSpace(out), "\n")), nil
+}
+
+// byTopDir buckets paths relative to the repo root by their top-level
+// directory, with files at the root under ".".
+func byTopDir(files []string) map[string][]string {
+	groups := map[string][]string{}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		top := "."
+		if i := strings.Index(file, "/"); i >= 0 {
+			top = file[:i]
+		}
+		groups[top] = append(groups[top], file)
+	}
+	return groups
+}
+
+// commitSplit turns everything staged into one commit per top-level
+// directory, after confirming the plan.
+func commitSplit(dir string, noVerify bool) error {
+	groups, err := stagedByDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("nothing to commit")
+	}
+	tops := []string{}
+	for top := range groups {
+		tops = append(tops, top)
+	}
+	sort.Strings(tops)
+	fmt.Println("commits:")
+	for _, top := range tops {
+		fmt.Printf("  update %v (%v files)\n", top, len(groups[top]))
+	}
+	confirmed, err := prompt.Confirm(fmt.Sprintf("Make %v commits?", len(tops)), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cli.ErrAborted
+	}
+	c := shell.NewWithDir(dir, "git reset -q")
+	_, err = c.RunCmd()
+	if err != nil {
+		return err
+	}
+	for _, top := range tops {
+		args := []string{"git", "add", "-A", "--"}
+		for _, file := range groups[top] {
+			// paths are relative to the repo root, not dir
+			args = append(args, ":(top)"+file)
+		}
+		add := shell.NewFromArrayWithDir(dir, args)
+		_, err = add.RunCmd()
+		if err != nil {
+			return err
+		}
+		message, err := withTicket(dir, "update "+top)
+		if err != nil {
+			return err
+		}
+		err = commit(dir, message, noVerify)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
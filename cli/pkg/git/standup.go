@@ -0,0 +1,72 @@
+package git
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+)
+
+// authorEmail is GITHUB_EMAIL, or git's global user.email when that isn't
+// configured.
+func authorEmail() (string, error) {
+	if config.GITHUB_EMAIL != "" {
+		return config.GITHUB_EMAIL, nil
+	}
+	c := shell.New("git config --global user.email").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil || strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("no author email. set github_email in %v", config.CONFIG_PATH)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Standup lists your commits on any branch of every repo since a time,
+// grouped by repo.
+func Standup(params []string) error {
+	flags := flag.NewFlagSet("standup", flag.ContinueOnError)
+	since := flags.String("since", "yesterday 9am", "show commits after this time, in any format git log --since accepts")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	email, err := authorEmail()
+	if err != nil {
+		return err
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	cmds := []shell.Cmd{}
+	for _, dir := range dirs {
+		cmds = append(cmds, shell.NewFromArrayWithDir(dir, []string{
+			"git", "log", "--all", "--oneline", "--no-decorate",
+			"--author=" + email, "--since=" + *since,
+		}).WithQuiet())
+	}
+	results := shell.RunCmdsConcurrent(cmds)
+	found := false
+	for i, dir := range dirs {
+		lines := parseOneline(results[i].Out)
+		if len(lines) == 0 {
+			continue
+		}
+		if found {
+			fmt.Println()
+		}
+		found = true
+		fmt.Println(colorize("1", path.Base(dir)))
+		for _, l := range lines {
+			fmt.Printf("  %v %v\n", colorize("33", l.Sha), l.Subject)
+		}
+	}
+	if !found {
+		fmt.Printf("no commits by %v since %v\n", email, *since)
+	}
+	return shell.Failed(results)
+}
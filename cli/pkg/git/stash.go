@@ -0,0 +1,83 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+func conflictedFiles(dir string) []string {
+	c := shell.NewWithDir(dir, "git diff --name-only --diff-filter=U").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil
+	}
+	files := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+func StashList(params []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	c := shell.NewWithDir(dir, "git stash list --format=%gd%x09%cr%x09%gs").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(out) == "" {
+		fmt.Println("no stashes")
+		return nil
+	}
+	t := table.New("STASH", "AGE", "MESSAGE").WithColor()
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		for len(fields) < 3 {
+			fields = append(fields, "")
+		}
+		t.AddRow(fields[0], fields[1], fields[2])
+	}
+	return t.Render(os.Stdout)
+}
+
+func StashPop(params []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	cmd := []string{"git", "stash", "pop"}
+	if len(params) > 0 {
+		cmd = append(cmd, params[0])
+	}
+	c := shell.NewFromArrayWithDir(dir, cmd)
+	_, err = c.RunCmd()
+	if err == nil {
+		return nil
+	}
+	conflicts := conflictedFiles(dir)
+	if len(conflicts) == 0 {
+		return err
+	}
+	return fmt.Errorf(
+		"stash pop hit conflicts in:\n  %v\nthe stash was kept. resolve the conflicts, then run `git stash drop`",
+		strings.Join(conflicts, "\n  "),
+	)
+}
+
+func StashSave(params []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	c := shell.NewFromArrayWithDir(dir, []string{"git", "stash", "push", "--include-untracked", "-m", strings.Join(params, " ")})
+	_, err = c.RunCmd()
+	return err
+}
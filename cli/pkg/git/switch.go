@@ -0,0 +1,83 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+)
+
+const recentRemoteBranches = 20
+const remoteSuffix = " (origin)"
+
+// remoteOnlyBranches returns the most recently updated origin branches that
+// don't have a local branch, without the "origin/" prefix.
+func remoteOnlyBranches(dir string, local []string) ([]string, error) {
+	remote, err := localBranches(dir, "-r", "--sort=-committerdate")
+	if err != nil {
+		return nil, err
+	}
+	branches := []string{}
+	for _, branch := range remote {
+		name := strings.TrimPrefix(branch, "origin/")
+		if name == branch || name == "HEAD" || comparable.Includes(local, name) {
+			continue
+		}
+		branches = append(branches, name)
+		if len(branches) == recentRemoteBranches {
+			break
+		}
+	}
+	return branches, nil
+}
+
+func pickBranch(local []string, remote []string) (string, error) {
+	options := append([]string{}, local...)
+	for _, branch := range remote {
+		options = append(options, branch+remoteSuffix)
+	}
+	selected, err := prompt.Select("Branch", options)
+	return strings.TrimSuffix(selected, remoteSuffix), err
+}
+
+func Switch(params []string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	local, err := localBranches(dir, "--sort=-committerdate")
+	if err != nil {
+		return err
+	}
+	remote, err := remoteOnlyBranches(dir, local)
+	if err != nil {
+		return err
+	}
+	var branch string
+	if len(params) > 0 {
+		branch = params[0]
+	} else {
+		branch, err = pickBranch(local, remote)
+		if err != nil {
+			return err
+		}
+	}
+	var cmd []string
+	switch {
+	case comparable.Includes(local, branch):
+		cmd = []string{"git", "switch", branch}
+	case comparable.Includes(remote, branch):
+		cmd = []string{"git", "switch", "-c", branch, "--track", "origin/" + branch}
+	default:
+		c := shell.NewWithDir(dir, "git rev-parse --verify --quiet origin/%v", branch).WithQuiet()
+		if _, err := c.RunCmd(); err != nil {
+			return fmt.Errorf("no local or origin branch named %v", branch)
+		}
+		cmd = []string{"git", "switch", "-c", branch, "--track", "origin/" + branch}
+	}
+	c := shell.NewFromArrayWithDir(dir, cmd)
+	_, err = c.RunCmd()
+	return err
+}
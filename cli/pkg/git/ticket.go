@@ -0,0 +1,47 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"toolbelt/internal/config"
+)
+
+// Ticket returns the ticket id in branch according to the configured
+// pattern, or "" if there isn't one.
+func Ticket(branch string) (string, error) {
+	if config.TICKET_PATTERN == "" {
+		return "", nil
+	}
+	re, err := regexp.Compile(config.TICKET_PATTERN)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticket_pattern %v: %v", config.TICKET_PATTERN, err)
+	}
+	match := re.FindStringSubmatch(branch)
+	switch {
+	case match == nil:
+		return "", nil
+	case len(match) > 1:
+		return match[1], nil
+	default:
+		return match[0], nil
+	}
+}
+
+// withTicket prefixes message with the ticket from the current branch, unless
+// there's no ticket or the message already mentions it.
+func withTicket(dir string, message string) (string, error) {
+	branch, err := CurrentBranch(dir)
+	if err != nil {
+		return message, nil
+	}
+	ticket, err := Ticket(branch)
+	if err != nil {
+		return "", err
+	}
+	if ticket == "" || strings.Contains(message, ticket) {
+		return message, nil
+	}
+	prefixed := strings.Replace(config.TICKET_FORMAT, "{ticket}", ticket, 1)
+	return strings.Replace(prefixed, "{message}", message, 1), nil
+}
@@ -0,0 +1,33 @@
+package jira
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/git"
+)
+
+// Open opens the Jira ticket named in the current branch.
+func Open(params []string) error {
+	if config.JIRA_URL == "" {
+		return fmt.Errorf("jira_url isn't set. run `toolbelt config set jira_url https://<org>.atlassian.net`")
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	branch, err := git.CurrentBranch(dir)
+	if err != nil {
+		return err
+	}
+	ticket, err := git.Ticket(branch)
+	if err != nil {
+		return err
+	}
+	if ticket == "" {
+		return fmt.Errorf("no ticket in branch %v. branches should match ticket_pattern %v", branch, config.TICKET_PATTERN)
+	}
+	return browser.Open(fmt.Sprintf("%v/browse/%v", strings.TrimSuffix(config.JIRA_URL, "/"), ticket))
+}
@@ -1,20 +1,74 @@
 package kill
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/ports"
 	"toolbelt/pkg/shell"
 )
 
 func Port(params []string) error {
-	c := shell.New("lsof -t -i:%v", params[0])
-	_, err := c.RunCmd()
+	flags := flag.NewFlagSet("kill", flag.ContinueOnError)
+	interactive := flags.Bool("i", false, "choose from all listening ports")
+	args, err := cli.ParseFlags(flags, params)
 	if err != nil {
-		return fmt.Errorf("couldn't run run `lsof -t -i:%v`. port is likely not in use", params[0])
+		return err
+	}
+	if *interactive {
+		return Interactive()
+	}
+	if len(args) == 0 {
+		return cli.Usagef("usage: kill <port> or kill -i")
 	}
-	c = shell.New("kill $(lsof -t -i:%v)", params[0])
-	_, err = c.RunCmd()
+	port, err := strconv.Atoi(args[0])
+	if err != nil {
+		return cli.Usagef("invalid port %v", args[0])
+	}
+	pids, err := ports.PIDsOnPort(port)
 	if err != nil {
 		return err
 	}
+	if len(pids) == 0 {
+		return fmt.Errorf("no process is listening on port %v", port)
+	}
+	return terminate(pids)
+}
+
+const termTimeout = 5 * time.Second
+
+func alive(p *os.Process) bool {
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate sends SIGTERM to each pid and escalates to SIGKILL for any that
+// are still running after termTimeout.
+func terminate(pids []int) error {
+	for _, pid := range pids {
+		fmt.Printf("kill %v\n", pid)
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		err = p.Signal(syscall.SIGTERM)
+		if err != nil {
+			return fmt.Errorf("couldn't kill pid %v: %v", pid, err)
+		}
+		err = shell.Poll(func() (bool, error) {
+			return !alive(p), nil
+		}, 100*time.Millisecond, termTimeout)
+		if err == nil {
+			continue
+		}
+		fmt.Printf("pid %v ignored SIGTERM for %v. sending SIGKILL\n", pid, termTimeout)
+		err = p.Signal(syscall.SIGKILL)
+		if err != nil && alive(p) {
+			return fmt.Errorf("couldn't kill pid %v: %v", pid, err)
+		}
+	}
 	return nil
 }
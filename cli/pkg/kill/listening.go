@@ -0,0 +1,40 @@
+package kill
+
+import (
+	"fmt"
+	"strconv"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/ports"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/tty"
+)
+
+func Interactive() error {
+	if !tty.IsInteractive() {
+		return fmt.Errorf("kill -i needs an interactive terminal")
+	}
+	listeners, err := ports.Listening()
+	if err != nil {
+		return err
+	}
+	if len(listeners) == 0 {
+		fmt.Println("no listening ports found")
+		return nil
+	}
+	options := []prompt.Option{}
+	for _, l := range listeners {
+		label := fmt.Sprintf("%-6v %-16v %-10v pid %v", l.Port, l.Command, l.User, l.PID)
+		options = append(options, prompt.Option{Label: label, Value: strconv.Itoa(l.PID)})
+	}
+	picked, err := prompt.MultiSelectOptions("Processes to kill", options)
+	if err != nil {
+		return err
+	}
+	selected := []int{}
+	for _, pid := range picked {
+		n, _ := strconv.Atoi(pid)
+		selected = append(selected, n)
+	}
+	// a process listening on several ports is listed once per port
+	return terminate(comparable.Unique(selected))
+}
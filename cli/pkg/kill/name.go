@@ -0,0 +1,87 @@
+package kill
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/shell"
+)
+
+type process struct {
+	pid     int
+	command string
+}
+
+// matching returns every process whose full command line matches pattern,
+// like `pgrep -f`. This process and its parent are skipped since the pattern
+// is on their command lines too.
+func matching(pattern *regexp.Regexp) ([]process, error) {
+	c := shell.New("ps -axo pid=,command=").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil, err
+	}
+	self, parent := os.Getpid(), os.Getppid()
+	result := []process{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil || pid == self || pid == parent {
+			continue
+		}
+		command := strings.TrimSpace(fields[1])
+		if pattern.MatchString(command) {
+			result = append(result, process{pid: pid, command: command})
+		}
+	}
+	return result, nil
+}
+
+// Name kills every process whose command line matches a pattern, after
+// confirming.
+func Name(params []string) error {
+	flags := flag.NewFlagSet("kill name", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list matching processes without killing them")
+	args, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return cli.Usagef("usage: kill name [--dry-run] <pattern>")
+	}
+	pattern, err := regexp.Compile(args[0])
+	if err != nil {
+		return cli.Usagef("invalid pattern %v: %v", args[0], err)
+	}
+	procs, err := matching(pattern)
+	if err != nil {
+		return err
+	}
+	if len(procs) == 0 {
+		return fmt.Errorf("no process matches %v", args[0])
+	}
+	pids := []int{}
+	for _, p := range procs {
+		fmt.Printf("%-8v %v\n", p.pid, p.command)
+		pids = append(pids, p.pid)
+	}
+	if *dryRun {
+		return nil
+	}
+	confirmed, err := prompt.Confirm(fmt.Sprintf("Kill %v processes?", len(pids)), false)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return cli.ErrAborted
+	}
+	return terminate(pids)
+}
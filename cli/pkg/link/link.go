@@ -0,0 +1,23 @@
+package link
+
+import (
+	"fmt"
+	"os"
+	"toolbelt/pkg/tty"
+)
+
+func Supported() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return tty.IsInteractive()
+}
+
+// Hyperlink wraps text in an OSC 8 escape sequence so terminals that support
+// it render a clickable link.
+func Hyperlink(url string, text string) string {
+	if !Supported() {
+		return text
+	}
+	return fmt.Sprintf("\x1b]8;;%v\x1b\\%v\x1b]8;;\x1b\\", url, text)
+}
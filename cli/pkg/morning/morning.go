@@ -0,0 +1,52 @@
+package morning
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"toolbelt/pkg/git"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/tty"
+)
+
+const identityTimeout = 15 * time.Second
+const loginTimeout = 2 * time.Minute
+
+func awsLogin() error {
+	if err := shell.Require("aws"); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(shell.Context(), identityTimeout)
+	defer cancel()
+	c := shell.New("aws sts get-caller-identity")
+	_, err := c.RunCmdContext(ctx)
+	if err == nil {
+		fmt.Println("aws session is still valid, skipping login")
+		return nil
+	}
+	if !tty.IsInteractive() {
+		fmt.Println("aws session expired. skipping aws sso login without a terminal")
+		return nil
+	}
+	ctx, cancel = context.WithTimeout(shell.Context(), loginTimeout)
+	defer cancel()
+	c = shell.New("aws sso login")
+	err = c.RunAttachedContext(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("aws sso login didn't finish within %v", loginTimeout)
+	}
+	return err
+}
+
+func Run(params []string) error {
+	err := awsLogin()
+	if err != nil {
+		return err
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	return git.PullRepos(dirs, shell.DefaultParallel)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Enabled turns on desktop notifications. Send is a no-op when it's off.
+var Enabled = false
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func command(title string, message string) ([]string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %v with title %v", appleScriptString(message), appleScriptString(title))
+		return []string{"osascript", "-e", script}, nil
+	case "linux":
+		return []string{"notify-send", title, message}, nil
+	default:
+		return nil, fmt.Errorf("notifications aren't supported on %v", runtime.GOOS)
+	}
+}
+
+// Program is the tool Send runs on this OS.
+func Program() (string, error) {
+	args, err := command("", "")
+	if err != nil {
+		return "", err
+	}
+	return args[0], nil
+}
+
+// Send shows a desktop notification with osascript on macOS or notify-send
+// on Linux.
+func Send(title string, message string) error {
+	if !Enabled {
+		return nil
+	}
+	args, err := command(title, message)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return fmt.Errorf("can't notify: %v not found", args[0])
+	}
+	return exec.Command(args[0], args[1:]...).Run()
+}
+
+// Done notifies that command finished, successfully or not.
+func Done(command string, err error) error {
+	if err != nil {
+		return Send("toolbelt", fmt.Sprintf("%v failed", command))
+	}
+	return Send("toolbelt", fmt.Sprintf("%v succeeded", command))
+}
@@ -0,0 +1,96 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"toolbelt/pkg/shell"
+)
+
+type PortInfo struct {
+	Port    int
+	PID     int
+	Command string
+	User    string
+}
+
+// parseLsof reads `lsof -nP -iTCP -sTCP:LISTEN` output, keeping one entry
+// per pid and port.
+func parseLsof(out string) []PortInfo {
+	seen := map[string]bool{}
+	infos := []PortInfo{}
+	lines := strings.Split(out, "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 9 {
+			continue
+		}
+		name := fields[len(fields)-2]
+		if fields[len(fields)-1] != "(LISTEN)" {
+			name = fields[len(fields)-1]
+		}
+		i := strings.LastIndex(name, ":")
+		if i < 0 {
+			continue
+		}
+		port, err := strconv.Atoi(name[i+1:])
+		if err != nil {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		key := fmt.Sprintf("%v:%v", pid, port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		infos = append(infos, PortInfo{Port: port, PID: pid, Command: fields[0], User: fields[2]})
+	}
+	return infos
+}
+
+// lsof runs lsof with args. lsof exits 1 without complaint when nothing
+// matches, so that's reported as no output. Any other failure, like a
+// permission error, is returned.
+func lsof(args ...string) (string, error) {
+	if err := shell.Require("lsof"); err != nil {
+		return "", err
+	}
+	c := shell.NewFromArray(append([]string{"lsof"}, args...)).WithQuiet()
+	out, err := c.RunCmd()
+	var cmdErr *shell.CmdError
+	if errors.As(err, &cmdErr) && cmdErr.ExitCode() == 1 && strings.TrimSpace(cmdErr.Stderr) == "" {
+		return "", nil
+	}
+	return out, err
+}
+
+// Listening returns every process listening on a TCP port.
+func Listening() ([]PortInfo, error) {
+	out, err := lsof("-nP", "-iTCP", "-sTCP:LISTEN")
+	if err != nil {
+		return nil, err
+	}
+	return parseLsof(out), nil
+}
+
+// PIDsOnPort returns the processes listening on port. On Linux this reads
+// /proc, so it works without lsof.
+func PIDsOnPort(port int) ([]int, error) {
+	if runtime.GOOS == "linux" {
+		return listeningPids(port)
+	}
+	out, err := lsof("-nP", fmt.Sprintf("-iTCP:%v", port), "-sTCP:LISTEN")
+	if err != nil {
+		return nil, err
+	}
+	pids := []int{}
+	for _, info := range parseLsof(out) {
+		pids = append(pids, info.PID)
+	}
+	return pids, nil
+}
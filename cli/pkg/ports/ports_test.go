@@ -0,0 +1,103 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"toolbelt/pkg/shell/shelltest"
+)
+
+func TestHelperProcess(t *testing.T) {
+	shelltest.HelperProcess()
+}
+
+const header = "COMMAND     PID   USER   FD   TYPE             DEVICE SIZE/OFF NODE NAME\n"
+
+func TestParseLsof(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []PortInfo
+	}{
+		{
+			"ipv4",
+			header + "node      12345  devon   23u  IPv4 0x1234567890abcdef      0t0  TCP 127.0.0.1:3000 (LISTEN)\n",
+			[]PortInfo{{Port: 3000, PID: 12345, Command: "node", User: "devon"}},
+		},
+		{
+			"ipv6",
+			header + "node      12345  devon   24u  IPv6 0xabcdef1234567890      0t0  TCP [::1]:3001 (LISTEN)\n",
+			[]PortInfo{{Port: 3001, PID: 12345, Command: "node", User: "devon"}},
+		},
+		{
+			"wildcard",
+			header + "postgres    678  devon    7u  IPv6 0x1111111111111111      0t0  TCP *:5432 (LISTEN)\n",
+			[]PortInfo{{Port: 5432, PID: 678, Command: "postgres", User: "devon"}},
+		},
+		{
+			"duplicate rows",
+			header +
+				"postgres    678  devon    7u  IPv6 0x1111111111111111      0t0  TCP *:5432 (LISTEN)\n" +
+				"postgres    678  devon    8u  IPv4 0x2222222222222222      0t0  TCP *:5432 (LISTEN)\n" +
+				"python3     999  devon    3u  IPv4 0x3333333333333333      0t0  TCP 127.0.0.1:8000 (LISTEN)\n" +
+				"python3     999  devon    4u  IPv6 0x4444444444444444      0t0  TCP [::1]:8000 (LISTEN)\n",
+			[]PortInfo{
+				{Port: 5432, PID: 678, Command: "postgres", User: "devon"},
+				{Port: 8000, PID: 999, Command: "python3", User: "devon"},
+			},
+		},
+		{
+			"header only",
+			header,
+			[]PortInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLsof(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeLsof puts an lsof on the PATH so shell.Require passes, and stubs
+// what it prints.
+func fakeLsof(t *testing.T, stdout string, stderr string, code int) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "lsof"), []byte("#!/bin/sh\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	shelltest.Install(t).Stub(stdout, stderr, code)
+}
+
+func TestLsofNoMatches(t *testing.T) {
+	fakeLsof(t, "", "", 1)
+	out, err := lsof("-iTCP:1")
+	if err != nil || out != "" {
+		t.Errorf("got %q, %v, want no output and no error", out, err)
+	}
+}
+
+func TestLsofFailure(t *testing.T) {
+	fakeLsof(t, "", "lsof: permission denied", 1)
+	_, err := lsof("-iTCP:1")
+	if err == nil {
+		t.Error("expected a permission error to be returned")
+	}
+}
+
+func TestLsofOutput(t *testing.T) {
+	want := header + "node 1 devon 23u IPv4 0x1 0t0 TCP *:3000 (LISTEN)\n"
+	fakeLsof(t, want, "", 0)
+	out, err := lsof("-iTCP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
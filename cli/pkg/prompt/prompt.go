@@ -0,0 +1,129 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+	"toolbelt/pkg/tty"
+
+	"github.com/charmbracelet/huh"
+)
+
+// AssumeYes answers yes to every confirmation without asking.
+var AssumeYes = false
+
+// ConfirmFunc, SelectFunc, MultiSelectFunc, and InputFunc ask the user.
+// They're huh forms by default and can be replaced, e.g. in tests.
+var ConfirmFunc = huhConfirm
+var SelectFunc = huhSelect
+var MultiSelectFunc = huhMultiSelect
+var InputFunc = huhInput
+
+// huhConfirm answers def without a terminal, since huh can't ask.
+func huhConfirm(msg string, def bool) (bool, error) {
+	if !tty.IsInteractive() {
+		if !def {
+			fmt.Printf("%v no. pass -y to answer yes without a terminal\n", msg)
+		}
+		return def, nil
+	}
+	confirmed := def
+	err := huh.NewConfirm().Title(msg).Value(&confirmed).Run()
+	return confirmed, err
+}
+
+func huhSelect(msg string, opts []string) (string, error) {
+	if !tty.IsInteractive() {
+		return "", fmt.Errorf("%v needs an interactive terminal", msg)
+	}
+	options := []huh.Option[string]{}
+	for _, opt := range opts {
+		options = append(options, huh.NewOption(opt, opt))
+	}
+	selected := ""
+	err := huh.NewSelect[string]().
+		Title(msg).
+		Description("/ to filter").
+		Options(options...).
+		Value(&selected).
+		Run()
+	return selected, err
+}
+
+func huhMultiSelect(msg string, opts []Option) ([]string, error) {
+	if !tty.IsInteractive() {
+		return nil, fmt.Errorf("%v needs an interactive terminal", msg)
+	}
+	options := []huh.Option[string]{}
+	selected := []string{}
+	for _, opt := range opts {
+		options = append(options, huh.NewOption(opt.Label, opt.Value))
+		if opt.Selected {
+			selected = append(selected, opt.Value)
+		}
+	}
+	err := huh.NewForm(huh.NewGroup(
+		huh.NewMultiSelect[string]().
+			Title(msg).
+			Options(options...).
+			Value(&selected),
+	)).Run()
+	return selected, err
+}
+
+func huhInput(msg string) (string, error) {
+	if !tty.IsInteractive() {
+		return "", fmt.Errorf("%v needs an interactive terminal", msg)
+	}
+	value := ""
+	err := huh.NewInput().Title(msg).Value(&value).Run()
+	return value, err
+}
+
+// Confirm asks a yes/no question. With -y it's yes, and without a terminal
+// it's def.
+func Confirm(msg string, def bool) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+	return ConfirmFunc(msg, def)
+}
+
+// Select asks the user to choose one of opts.
+func Select(msg string, opts []string) (string, error) {
+	if len(opts) == 0 {
+		return "", fmt.Errorf("nothing to choose from")
+	}
+	return SelectFunc(msg, opts)
+}
+
+// Option is a choice shown as Label that answers Value, checked up front
+// when Selected.
+type Option struct {
+	Label    string
+	Value    string
+	Selected bool
+}
+
+// MultiSelect asks the user to choose any number of opts.
+func MultiSelect(msg string, opts []string) ([]string, error) {
+	options := []Option{}
+	for _, opt := range opts {
+		options = append(options, Option{Label: opt, Value: opt})
+	}
+	return MultiSelectOptions(msg, options)
+}
+
+// MultiSelectOptions is MultiSelect with labels and preselected options,
+// returning the chosen values.
+func MultiSelectOptions(msg string, opts []Option) ([]string, error) {
+	if len(opts) == 0 {
+		return nil, fmt.Errorf("nothing to choose from")
+	}
+	return MultiSelectFunc(msg, opts)
+}
+
+// Input asks the user for a line of text.
+func Input(msg string) (string, error) {
+	value, err := InputFunc(msg)
+	return strings.TrimSpace(value), err
+}
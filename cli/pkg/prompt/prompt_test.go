@@ -0,0 +1,72 @@
+package prompt
+
+import "testing"
+
+func TestConfirmUsesConfirmFunc(t *testing.T) {
+	defer func(f func(string, bool) (bool, error)) { ConfirmFunc = f }(ConfirmFunc)
+	asked := ""
+	ConfirmFunc = func(msg string, def bool) (bool, error) {
+		asked = msg
+		return true, nil
+	}
+	ok, err := Confirm("delete?", false)
+	if err != nil || !ok || asked != "delete?" {
+		t.Errorf("got %v, %v, asked %q", ok, err, asked)
+	}
+}
+
+func TestConfirmAssumeYes(t *testing.T) {
+	defer func(f func(string, bool) (bool, error)) { ConfirmFunc = f }(ConfirmFunc)
+	defer func() { AssumeYes = false }()
+	ConfirmFunc = func(string, bool) (bool, error) {
+		t.Error("ConfirmFunc called despite AssumeYes")
+		return false, nil
+	}
+	AssumeYes = true
+	if ok, _ := Confirm("delete?", false); !ok {
+		t.Error("expected yes")
+	}
+}
+
+func TestSelectUsesSelectFunc(t *testing.T) {
+	defer func(f func(string, []string) (string, error)) { SelectFunc = f }(SelectFunc)
+	SelectFunc = func(msg string, opts []string) (string, error) {
+		return opts[1], nil
+	}
+	got, err := Select("pick", []string{"a", "b"})
+	if err != nil || got != "b" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if _, err := Select("pick", nil); err == nil {
+		t.Error("expected an error with no options")
+	}
+}
+
+func TestDefaultsWithoutTerminal(t *testing.T) {
+	// go test's stdout isn't a terminal, so the huh funcs fall back.
+	if ok, err := huhConfirm("delete?", true); err != nil || !ok {
+		t.Errorf("got %v, %v, want the default", ok, err)
+	}
+	if _, err := huhSelect("pick", []string{"a"}); err == nil {
+		t.Error("expected an error without a terminal")
+	}
+}
+
+func TestMultiSelectUsesMultiSelectFunc(t *testing.T) {
+	defer func(f func(string, []Option) ([]string, error)) { MultiSelectFunc = f }(MultiSelectFunc)
+	var asked []Option
+	MultiSelectFunc = func(msg string, opts []Option) ([]string, error) {
+		asked = opts
+		return []string{opts[0].Value, opts[1].Value}, nil
+	}
+	got, err := MultiSelect("pick", []string{"a", "b", "c"})
+	if err != nil || len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if asked[2] != (Option{Label: "c", Value: "c"}) {
+		t.Errorf("got option %+v", asked[2])
+	}
+	if _, err := MultiSelect("pick", nil); err == nil {
+		t.Error("expected an error with no options")
+	}
+}
@@ -0,0 +1,199 @@
+package repo
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/browser"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+func newFlags(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+type sharedFlags struct {
+	envOverride *bool
+	repoName    *string
+	args        []string
+}
+
+// parse registers the flags shared by every dev command, on top of any the
+// caller already registered on flags, and parses params.
+func parse(flags *flag.FlagSet, params []string) (sharedFlags, error) {
+	shared := sharedFlags{
+		envOverride: flags.Bool("env-override", false, "let .env values override the process environment"),
+		repoName:    flags.String("repo", "", "run in the named repo under the repos path instead of the current one"),
+	}
+	args, err := cli.ParseFlags(flags, params)
+	shared.args = args
+	return shared, err
+}
+
+// resolve parses the flags shared by every dev command and finds the repo to
+// run in.
+func resolve(flags *flag.FlagSet, params []string, envFiles ...string) (Repo, Options, error) {
+	shared, err := parse(flags, params)
+	if err != nil {
+		return nil, Options{}, err
+	}
+	return target(shared, envFiles)
+}
+
+func target(shared sharedFlags, envFiles []string) (Repo, Options, error) {
+	var r Repo
+	var err error
+	opts := Options{Params: shared.args}
+	if *shared.repoName != "" {
+		r, err = ByName(*shared.repoName)
+		if err != nil {
+			return nil, Options{}, err
+		}
+		opts.Dir, err = Dir(*shared.repoName)
+		if err != nil {
+			return nil, Options{}, err
+		}
+	} else {
+		r, err = current()
+		if err != nil {
+			return nil, Options{}, err
+		}
+		// run from the root so commands behave the same from any subdirectory
+		opts.Dir, err = r.Root()
+		if err != nil {
+			return nil, Options{}, err
+		}
+	}
+	opts.Env, err = loadEnv(opts.Dir, envFiles, *shared.envOverride)
+	if err != nil {
+		return nil, Options{}, err
+	}
+	return r, opts, nil
+}
+
+// runDev runs do in the current repo, or with --all in every recognized
+// repo under the repos path. parallel is the default --parallel for --all.
+func runDev(name string, params []string, parallel int, do func(Repo, Options) error, envFiles ...string) error {
+	flags := newFlags(name)
+	all := flags.Bool("all", false, "run in every recognized repo under the repos path and report which failed")
+	limit := cli.ParallelFlag{N: parallel}
+	flags.Var(&limit, "parallel", "with --all, how many repos to run in at once. 0 or max for unbounded")
+	shared, err := parse(flags, params)
+	if err != nil {
+		return err
+	}
+	if !*all {
+		r, opts, err := target(shared, envFiles)
+		if err != nil {
+			return err
+		}
+		return do(r, opts)
+	}
+	if *shared.repoName != "" {
+		return cli.Usagef("--all and --repo can't be used together")
+	}
+	return runAll(shared, envFiles, limit.N, do)
+}
+
+func runAll(shared sharedFlags, envFiles []string, parallel int, do func(Repo, Options) error) error {
+	names := []string{}
+	for _, r := range all {
+		if _, err := Dir(r.Name()); err == nil {
+			names = append(names, r.Name())
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no recognized repos in %v", config.REPOS_PATH)
+	}
+	errs := make([]error, len(names))
+	shell.ForEachN(parallel, len(names), func(i int) {
+		repoName := names[i]
+		r, opts, err := target(sharedFlags{envOverride: shared.envOverride, repoName: &repoName, args: shared.args}, envFiles)
+		if err == nil {
+			err = do(r, opts)
+		}
+		errs[i] = err
+	})
+	t := table.New("REPO", "RESULT").WithColor()
+	failed := 0
+	for i, name := range names {
+		if errs[i] != nil {
+			failed++
+			t.AddRow(name, "fail")
+		} else {
+			t.AddRow(name, "pass")
+		}
+	}
+	err := t.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%v of %v repos failed", failed, len(names))
+	}
+	return nil
+}
+
+func current() (Repo, error) {
+	r := Current()
+	if r == nil {
+		return nil, fmt.Errorf("not in a recognized repo")
+	}
+	return r, nil
+}
+
+// Test runs one repo at a time with --all unless --parallel is given, so
+// the output stays readable.
+func Test(params []string) error {
+	return runDev("dev test", params, 1, Repo.Test, ".env", ".env.test")
+}
+
+func Run(params []string) error {
+	r, opts, err := resolve(newFlags("dev run"), params, ".env")
+	if err != nil {
+		return err
+	}
+	return r.Run(opts)
+}
+
+func Lint(params []string) error {
+	return runDev("dev lint", params, shell.DefaultParallel, Repo.Lint, ".env")
+}
+
+func Format(params []string) error {
+	return runDev("dev format", params, shell.DefaultParallel, Repo.Format, ".env")
+}
+
+func Setup(params []string) error {
+	return runDev("dev setup", params, shell.DefaultParallel, Repo.Setup, ".env")
+}
+
+func Coverage(params []string) error {
+	flags := newFlags("dev coverage")
+	open := flags.Bool("open", false, "open the HTML report in the browser")
+	r, opts, err := resolve(flags, params, ".env", ".env.test")
+	if err != nil {
+		return err
+	}
+	report, err := r.Coverage(opts)
+	if err != nil {
+		return err
+	}
+	if !*open || report == "" {
+		return nil
+	}
+	return browser.Open("file://" + path.Join(opts.Dir, report))
+}
+
+func Which(params []string) error {
+	name := Detect()
+	if name == "" {
+		name = "unknown"
+	}
+	fmt.Println(name)
+	return nil
+}
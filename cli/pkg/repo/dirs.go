@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/fs"
+)
+
+// Dirs returns every cloned repo under REPOS_PATH, or matching REPOS_GLOB
+// when it's set, skipping the archive.
+func Dirs() ([]string, error) {
+	pattern := config.REPOS_GLOB
+	if pattern == "" {
+		pattern = path.Join(config.REPOS_PATH, "*")
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repos glob %v: %v", pattern, err)
+	}
+	dirs := []string{}
+	for _, dir := range matches {
+		if dir == config.ARCHIVE_PATH || strings.HasPrefix(dir, config.ARCHIVE_PATH+"/") {
+			continue
+		}
+		if !fs.Exists(path.Join(dir, ".git")) {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// Dir returns where the repo called name is cloned, among Dirs.
+func Dir(name string) (string, error) {
+	dirs, err := Dirs()
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		if path.Base(dir) == name {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("%v isn't cloned under %v", name, config.REPOS_PATH)
+}
@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"os"
+	"path"
+	"testing"
+	"toolbelt/internal/config"
+)
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	defer func(p, g, a string) { config.REPOS_PATH, config.REPOS_GLOB, config.ARCHIVE_PATH = p, g, a }(config.REPOS_PATH, config.REPOS_GLOB, config.ARCHIVE_PATH)
+	config.REPOS_PATH = root
+	config.REPOS_GLOB = path.Join(root, "*", "*")
+	config.ARCHIVE_PATH = path.Join(root, "archive")
+	for _, dir := range []string{"org/metricflow/.git", "archive/old/.git", "org/not-a-repo"} {
+		if err := os.MkdirAll(path.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Dir("metricflow")
+	if err != nil || got != path.Join(root, "org", "metricflow") {
+		t.Errorf("got %q, %v", got, err)
+	}
+	for _, name := range []string{"old", "not-a-repo", "metricflow-server"} {
+		if _, err := Dir(name); err == nil {
+			t.Errorf("expected %v not to be found", name)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package repo
 
 import "toolbelt/pkg/shell"
 
-type DbtSemanticInterfaces struct{}
+type DbtSemanticInterfaces struct{ checkout }
+
+func (r DbtSemanticInterfaces) Name() string {
+	return "dbt-semantic-interfaces"
+}
 
 func (r DbtSemanticInterfaces) Reviewers() []string {
 	return []string{
@@ -12,26 +16,30 @@ func (r DbtSemanticInterfaces) Reviewers() []string {
 	}
 }
 
-func (r DbtSemanticInterfaces) Test() error {
-	c := shell.New("make test")
-	_, err := c.RunCmd()
-	return err
+func (r DbtSemanticInterfaces) Test(opts Options) error {
+	return run(opts, "make test")
+}
+
+func (r DbtSemanticInterfaces) Run(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r DbtSemanticInterfaces) Lint(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r DbtSemanticInterfaces) Format(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r DbtSemanticInterfaces) Run() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r DbtSemanticInterfaces) Setup(opts Options) error {
+	return run(opts, "poetry install")
 }
 
-func (r DbtSemanticInterfaces) Lint() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r DbtSemanticInterfaces) Coverage(opts Options) (string, error) {
+	return "htmlcov/index.html", run(opts, "poetry run pytest --cov --cov-report=term --cov-report=html")
 }
 
-func (r DbtSemanticInterfaces) Format() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r DbtSemanticInterfaces) PreSave() []shell.Cmd {
+	return nil
 }
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// loadEnv reads the given dotenv files from dir in order, skipping any that
+// don't exist. Unless override is set, keys already in the process
+// environment are dropped so the process environment wins.
+func loadEnv(dir string, files []string, override bool) ([]string, error) {
+	env := []string{}
+	for _, name := range files {
+		vars, err := parseEnvFile(path.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vars {
+			key, _, _ := strings.Cut(v, "=")
+			if _, set := os.LookupEnv(key); set && !override {
+				continue
+			}
+			env = append(env, v)
+		}
+	}
+	return env, nil
+}
+
+func parseEnvFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	vars := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars = append(vars, key+"="+value)
+	}
+	return vars, scanner.Err()
+}
@@ -2,7 +2,11 @@ package repo
 
 import "toolbelt/pkg/shell"
 
-type Metricflow struct{}
+type Metricflow struct{ checkout }
+
+func (r Metricflow) Name() string {
+	return "metricflow"
+}
 
 func (r Metricflow) Reviewers() []string {
 	return []string{
@@ -12,26 +16,30 @@ func (r Metricflow) Reviewers() []string {
 	}
 }
 
-func (r Metricflow) Test() error {
-	c := shell.New("make test")
-	_, err := c.RunCmd()
-	return err
+func (r Metricflow) Test(opts Options) error {
+	return run(opts, "make test")
+}
+
+func (r Metricflow) Run(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r Metricflow) Lint(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r Metricflow) Format(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r Metricflow) Run() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r Metricflow) Setup(opts Options) error {
+	return run(opts, "poetry install")
 }
 
-func (r Metricflow) Lint() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r Metricflow) Coverage(opts Options) (string, error) {
+	return "htmlcov/index.html", run(opts, "poetry run pytest --cov --cov-report=term --cov-report=html")
 }
 
-func (r Metricflow) Format() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r Metricflow) PreSave() []shell.Cmd {
+	return nil
 }
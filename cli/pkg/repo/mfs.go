@@ -2,7 +2,11 @@ package repo
 
 import "toolbelt/pkg/shell"
 
-type MetricflowServer struct{}
+type MetricflowServer struct{ checkout }
+
+func (r MetricflowServer) Name() string {
+	return "metricflow-server"
+}
 
 func (r MetricflowServer) Reviewers() []string {
 	return []string{
@@ -11,26 +15,30 @@ func (r MetricflowServer) Reviewers() []string {
 	}
 }
 
-func (r MetricflowServer) Test() error {
-	c := shell.New("make test")
-	_, err := c.RunCmd()
-	return err
+func (r MetricflowServer) Test(opts Options) error {
+	return run(opts, "make test")
+}
+
+func (r MetricflowServer) Run(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r MetricflowServer) Lint(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r MetricflowServer) Format(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r MetricflowServer) Run() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r MetricflowServer) Setup(opts Options) error {
+	return run(opts, "poetry install")
 }
 
-func (r MetricflowServer) Lint() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r MetricflowServer) Coverage(opts Options) (string, error) {
+	return "htmlcov/index.html", run(opts, "poetry run pytest --cov --cov-report=term --cov-report=html")
 }
 
-func (r MetricflowServer) Format() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r MetricflowServer) PreSave() []shell.Cmd {
+	return nil
 }
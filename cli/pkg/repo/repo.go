@@ -3,33 +3,165 @@ package repo
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"toolbelt/pkg/shell"
 )
 
+type Options struct {
+	// Dir is where commands run. Empty means the current directory.
+	Dir string
+	Env []string
+	// Params are passed through to the underlying tool, e.g. the `-v` in
+	// `dev test -- -v`.
+	Params []string
+}
+
 type Repo interface {
+	// Name is the repo's directory name, as used by --repo.
+	Name() string
+	// Root is the top level of the repo's checkout that contains the
+	// current directory.
+	Root() (string, error)
 	Reviewers() []string
-	Test() error
-	Run() error
-	Lint() error
-	Format() error
+	Test(opts Options) error
+	Run(opts Options) error
+	Lint(opts Options) error
+	Format(opts Options) error
+	// Setup installs the repo's dependencies, e.g. after cloning it.
+	Setup(opts Options) error
+	// Coverage runs the tests with coverage and returns the path of the
+	// HTML report relative to the repo root, if one is written.
+	Coverage(opts Options) (string, error)
+	// PreSave are run from the repo root before `git save` stages changes,
+	// e.g. a formatter. Empty when there's nothing to run.
+	PreSave() []shell.Cmd
 }
 
-func Current() Repo {
+var errNoCoverage = fmt.Errorf("coverage is not configured for this repo")
+var errNoSetup = fmt.Errorf("no setup configured for this repo")
+
+// Root returns the top level of the git checkout dir is in. An empty dir is
+// the current directory.
+func Root(dir string) (string, error) {
+	c := shell.New("git rev-parse --show-toplevel").WithQuiet()
+	if dir != "" {
+		c = shell.NewWithDir(dir, "git rev-parse --show-toplevel").WithQuiet()
+	}
+	out, err := c.RunCmd()
+	if err != nil {
+		return "", fmt.Errorf("couldn't find the repo root: %w", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// OptionsIn returns the Options `dev` commands use for the repo dir is in:
+// run from its root, with envFiles loaded from there.
+func OptionsIn(dir string, envFiles ...string) (Options, error) {
+	root, err := Root(dir)
+	if err != nil {
+		return Options{}, err
+	}
+	env, err := loadEnv(root, envFiles, false)
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{Dir: root, Env: env}, nil
+}
+
+func run(opts Options, cmd string) error {
+	c := shell.New(cmd)
+	if opts.Dir != "" {
+		c = shell.NewWithDir(opts.Dir, cmd)
+	}
+	c = c.WithEnv(opts.Env).WithArgs(opts.Params...)
+	_, err := c.RunCmd()
+	return err
+}
+
+// checkout gives every repo the same Root, since they're all git checkouts.
+type checkout struct{}
+
+func (checkout) Root() (string, error) {
+	return Root("")
+}
+
+// all is checked in order by Detect, so repos whose name contains another
+// repo's name, like metricflow-server, must come first.
+var all = []Repo{
+	MetricflowServer{},
+	Metricflow{},
+	DbtSemanticInterfaces{},
+	SemanticLayerGateway{},
+}
+
+var byName = func() map[string]Repo {
+	m := map[string]Repo{}
+	for _, r := range all {
+		m[r.Name()] = r
+	}
+	return m
+}()
+
+func ByName(name string) (Repo, error) {
+	r, ok := byName[name]
+	if !ok {
+		names := []string{}
+		for n := range byName {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown repo %v. known repos: %v", name, strings.Join(names, ", "))
+	}
+	return r, nil
+}
+
+var detected struct {
+	dir  string
+	name string
+}
+
+// Detect returns the name of the repo the current directory belongs to, or
+// "" if it isn't a recognized repo. The result is cached per directory.
+func Detect() string {
 	directory, err := os.Getwd()
 	if err != nil {
 		fmt.Println(err)
 	}
-	if strings.Contains(directory, "metricflow-server") {
-		return MetricflowServer{}
+	if detected.dir == directory && directory != "" {
+		return detected.name
 	}
-	if strings.Contains(directory, "metricflow") {
-		return Metricflow{}
+	detected.dir = directory
+	detected.name = detectIn(directory)
+	return detected.name
+}
+
+func detectIn(directory string) string {
+	for _, r := range all {
+		if strings.Contains(directory, r.Name()) {
+			return r.Name()
+		}
 	}
-	if strings.Contains(directory, "dbt-semantic-interfaces") {
-		return DbtSemanticInterfaces{}
+	return ""
+}
+
+// In returns the repo dir belongs to, or nil if it isn't a recognized repo.
+func In(dir string) Repo {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
 	}
-	if strings.Contains(directory, "semantic-layer-gateway") {
-		return SemanticLayerGateway{}
+	name := detectIn(dir)
+	if name == "" {
+		return nil
+	}
+	return byName[name]
+}
+
+func Current() Repo {
+	name := Detect()
+	if name == "" {
+		return nil
 	}
-	return nil
+	return byName[name]
 }
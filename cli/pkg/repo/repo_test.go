@@ -0,0 +1,27 @@
+package repo
+
+import "testing"
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/git/metricflow-server/app", "metricflow-server"},
+		{"/git/metricflow", "metricflow"},
+		{"/git/dbt-semantic-interfaces", "dbt-semantic-interfaces"},
+		{"/git/semantic-layer-gateway/src", "semantic-layer-gateway"},
+	}
+	for _, tt := range tests {
+		r := In(tt.dir)
+		if r == nil || r.Name() != tt.want {
+			t.Errorf("In(%v) = %v, want %v", tt.dir, r, tt.want)
+		}
+		if byName, err := ByName(tt.want); err != nil || byName != r {
+			t.Errorf("ByName(%v) = %v, %v", tt.want, byName, err)
+		}
+	}
+	if r := In("/git/other"); r != nil {
+		t.Errorf("In(/git/other) = %v, want nil", r)
+	}
+}
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"fmt"
+	"toolbelt/pkg/link"
+)
+
+func PrintReviewers() error {
+	r, err := current()
+	if err != nil {
+		return err
+	}
+	for _, reviewer := range r.Reviewers() {
+		url := fmt.Sprintf("https://github.com/%v", reviewer)
+		if link.Supported() {
+			fmt.Println(link.Hyperlink(url, reviewer))
+		} else {
+			fmt.Printf("%v (%v)\n", reviewer, url)
+		}
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package repo
 
 import "toolbelt/pkg/shell"
 
-type SemanticLayerGateway struct{}
+type SemanticLayerGateway struct{ checkout }
+
+func (r SemanticLayerGateway) Name() string {
+	return "semantic-layer-gateway"
+}
 
 func (r SemanticLayerGateway) Reviewers() []string {
 	return []string{
@@ -11,26 +15,30 @@ func (r SemanticLayerGateway) Reviewers() []string {
 	}
 }
 
-func (r SemanticLayerGateway) Test() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r SemanticLayerGateway) Test(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r SemanticLayerGateway) Run() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r SemanticLayerGateway) Run(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r SemanticLayerGateway) Lint() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
+func (r SemanticLayerGateway) Lint(opts Options) error {
+	return run(opts, "test")
 }
 
-func (r SemanticLayerGateway) Format() error {
-	c := shell.New("test")
-	_, err := c.RunCmd()
-	return err
-}
\ No newline at end of file
+func (r SemanticLayerGateway) Format(opts Options) error {
+	return run(opts, "test")
+}
+
+func (r SemanticLayerGateway) Setup(opts Options) error {
+	return errNoSetup
+}
+
+func (r SemanticLayerGateway) Coverage(opts Options) (string, error) {
+	return "", errNoCoverage
+}
+
+func (r SemanticLayerGateway) PreSave() []shell.Cmd {
+	return []shell.Cmd{shell.New("gradle ktlintFormat")}
+}
@@ -0,0 +1,101 @@
+package repos
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/prompt"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+)
+
+// lastActive is the later of a repo's last commit and its last fetch.
+func lastActive(dir string) (time.Time, error) {
+	c := shell.NewWithDir(dir, "git log -1 --format=%ct").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't read the last commit time of %v: %v", dir, err)
+	}
+	last := time.Unix(seconds, 0)
+	info, err := os.Stat(path.Join(dir, ".git", "FETCH_HEAD"))
+	if err == nil && info.ModTime().After(last) {
+		last = info.ModTime()
+	}
+	return last, nil
+}
+
+// Archive moves repos that haven't been committed to or fetched in a while
+// into ARCHIVE_PATH, where git pull and the other repos commands skip them.
+// With -y every stale repo is archived without asking.
+func Archive(params []string) error {
+	flags := flag.NewFlagSet("repos archive", flag.ContinueOnError)
+	days := flags.Int("days", config.ARCHIVE_AFTER_DAYS, "how many days without a commit or fetch makes a repo stale")
+	dryRun := flags.Bool("dry-run", false, "list stale repos without moving them")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().AddDate(0, 0, -*days)
+	stale := []string{}
+	options := []prompt.Option{}
+	for _, dir := range dirs {
+		last, err := lastActive(dir)
+		if err != nil {
+			fmt.Printf("skipping %v: %v\n", path.Base(dir), err)
+			continue
+		}
+		if last.After(cutoff) {
+			continue
+		}
+		label := fmt.Sprintf("%v (last active %v)", path.Base(dir), last.Format("2006-01-02"))
+		stale = append(stale, dir)
+		options = append(options, prompt.Option{Label: label, Value: dir})
+		if *dryRun {
+			fmt.Println(label)
+		}
+	}
+	if len(stale) == 0 {
+		fmt.Printf("no repos inactive for more than %v days\n", *days)
+		return nil
+	}
+	if *dryRun {
+		return nil
+	}
+	selected := stale
+	if !prompt.AssumeYes {
+		selected, err = prompt.MultiSelectOptions("Repos to archive", options)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.MkdirAll(config.ARCHIVE_PATH, 0755)
+	if err != nil {
+		return err
+	}
+	for _, dir := range selected {
+		dest := path.Join(config.ARCHIVE_PATH, path.Base(dir))
+		if _, err := os.Stat(dest); err == nil {
+			return fmt.Errorf("can't archive %v: %v already exists", dir, dest)
+		}
+		err = os.Rename(dir, dest)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("archived %v to %v\n", path.Base(dir), dest)
+	}
+	return nil
+}
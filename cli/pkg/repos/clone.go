@@ -0,0 +1,89 @@
+package repos
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/fs"
+	"toolbelt/pkg/shell"
+)
+
+// Clone clones every configured repo that isn't already in the repos path,
+// so it can be re-run to pick up repos that failed last time.
+func Clone(params []string) error {
+	flags := flag.NewFlagSet("repos clone", flag.ContinueOnError)
+	parallel := cli.ParallelFlag{N: shell.DefaultParallel}
+	flags.Var(&parallel, "parallel", "how many repos to clone at once. 0 or max for unbounded")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if len(config.REPOS) == 0 {
+		return fmt.Errorf("no repos configured. add org/name entries under repos in %v", config.CONFIG_PATH)
+	}
+	urls := []string{}
+	dests := []string{}
+	skipped := 0
+	for _, repo := range config.REPOS {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid repo %v. expected org/name", repo)
+		}
+		dest := path.Join(config.REPOS_PATH, parts[1])
+		if fs.Exists(dest) {
+			skipped++
+			continue
+		}
+		urls = append(urls, fmt.Sprintf("git@github.com:%v.git", repo))
+		dests = append(dests, dest)
+	}
+	if len(urls) == 0 {
+		fmt.Println("every configured repo is already cloned")
+		return nil
+	}
+	errs := make([]error, len(urls))
+	shell.ForEachN(parallel.N, len(urls), func(i int) {
+		errs[i] = cloneWithRetry(urls[i], dests[i])
+	})
+	failed := []string{}
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%v: %v", path.Base(dests[i]), err))
+		}
+	}
+	fmt.Printf("%v cloned, %v skipped, %v failed\n", len(urls)-len(failed), skipped, len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("couldn't clone, re-run to retry:\n%v", strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+const cloneAttempts = 3
+const cloneBackoff = 2 * time.Second
+
+// cloneWithRetry clones url into dest, retrying transient failures with
+// exponential backoff. A failed attempt's partial checkout is removed so the
+// next attempt, or the next run, starts clean.
+func cloneWithRetry(url string, dest string) error {
+	var err error
+	backoff := cloneBackoff
+	for attempt := 1; attempt <= cloneAttempts; attempt++ {
+		c := shell.NewFromArray([]string{"git", "clone", url, dest})
+		_, err = c.RunCmd()
+		if err == nil {
+			return nil
+		}
+		os.RemoveAll(dest)
+		if attempt < cloneAttempts {
+			fmt.Printf("cloning %v failed, retrying in %v\n", url, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package repos
+
+import (
+	"flag"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+)
+
+func Exec(params []string) error {
+	flags := flag.NewFlagSet("repos exec", flag.ContinueOnError)
+	parallel := cli.ParallelFlag{N: shell.DefaultParallel}
+	flags.Var(&parallel, "parallel", "how many repos to run in at once. 0 or max for unbounded")
+	failFast := flags.Bool("fail-fast", false, "stop at the first failure instead of running in every repo")
+	args, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return cli.Usagef("usage: repos exec [--parallel N] [--fail-fast] -- <command...>")
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	cmds := []shell.Cmd{}
+	for _, dir := range dirs {
+		cmds = append(cmds, shell.NewFromArrayWithDir(dir, args))
+	}
+	if *failFast {
+		return shell.Failed(shell.RunCmdsConcurrentFailFast(cmds, parallel.N))
+	}
+	return shell.Failed(shell.RunCmdsConcurrentN(cmds, parallel.N))
+}
@@ -0,0 +1,51 @@
+package repos
+
+import (
+	"flag"
+	"os"
+	"path"
+	"toolbelt/pkg/cli"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+// FetchAll fetches every repo without touching working trees and reports
+// which ones are behind their upstream.
+func FetchAll(params []string) error {
+	flags := flag.NewFlagSet("git fetch-all", flag.ContinueOnError)
+	parallel := cli.ParallelFlag{N: shell.DefaultParallel}
+	flags.Var(&parallel, "parallel", "how many repos to fetch at once. 0 or max for unbounded")
+	_, err := cli.ParseFlags(flags, params)
+	if err != nil {
+		return err
+	}
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	fetches := []shell.Cmd{}
+	for _, dir := range dirs {
+		fetches = append(fetches, shell.NewWithDir(dir, "git fetch --all"))
+	}
+	fetched := shell.RunCmdsConcurrentN(fetches, parallel.N)
+	t := table.New("REPO", "BEHIND", "STATUS").WithColor()
+	for i, dir := range dirs {
+		_, behind, err := aheadBehind(dir)
+		switch {
+		case fetched[i].Err != nil:
+			t.AddRow(path.Base(dir), "", "fetch failed")
+		case err != nil:
+			t.AddRow(path.Base(dir), "", "no upstream")
+		case behind > 0:
+			t.AddRow(path.Base(dir), behind, "behind")
+		default:
+			t.AddRow(path.Base(dir), 0, "up to date")
+		}
+	}
+	err = t.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return shell.Failed(fetched)
+}
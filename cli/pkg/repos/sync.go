@@ -0,0 +1,90 @@
+package repos
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"toolbelt/pkg/repo"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/table"
+)
+
+type syncStatus struct {
+	repo   string
+	ahead  int
+	behind int
+	status string
+}
+
+// aheadBehind compares HEAD with its upstream after a fetch.
+func aheadBehind(dir string) (int, int, error) {
+	c := shell.NewWithDir(dir, "git rev-list --left-right --count HEAD...@{upstream}").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %v", out)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+func Sync(params []string) error {
+	dirs, err := repo.Dirs()
+	if err != nil {
+		return err
+	}
+	fetches := []shell.Cmd{}
+	for _, dir := range dirs {
+		fetches = append(fetches, shell.NewWithDir(dir, "git fetch"))
+	}
+	fetched := shell.RunCmdsConcurrent(fetches)
+	statuses := []syncStatus{}
+	pulls := []shell.Cmd{}
+	pulled := []int{}
+	for i, dir := range dirs {
+		s := syncStatus{repo: path.Base(dir)}
+		s.ahead, s.behind, err = aheadBehind(dir)
+		switch {
+		case fetched[i].Err != nil:
+			s.status = "fetch failed"
+		case err != nil:
+			s.status = "no upstream"
+		case s.ahead > 0:
+			s.status = "skipped, has unpushed commits"
+		case s.behind > 0:
+			s.status = "pulled"
+			pulls = append(pulls, shell.NewWithDir(dir, "git pull --ff-only"))
+			pulled = append(pulled, len(statuses))
+		default:
+			s.status = "up to date"
+		}
+		statuses = append(statuses, s)
+	}
+	results := shell.RunCmdsConcurrent(pulls)
+	for _, r := range results {
+		if r.Err != nil {
+			statuses[pulled[r.Index]].status = "pull failed"
+		}
+	}
+	t := table.New("REPO", "AHEAD", "BEHIND", "STATUS").WithColor()
+	for _, s := range statuses {
+		t.AddRow(s.repo, s.ahead, s.behind, s.status)
+	}
+	err = t.Render(os.Stdout)
+	if err != nil {
+		return err
+	}
+	return shell.Failed(append(fetched, results...))
+}
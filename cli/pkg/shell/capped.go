@@ -0,0 +1,40 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+)
+
+// cappedWriter buffers up to max bytes. Once the cap is hit, what was
+// buffered so far and everything after it is streamed to overflow (if set)
+// instead, so huge outputs are still visible without being held in memory.
+type cappedWriter struct {
+	buf       bytes.Buffer
+	max       int
+	overflow  io.Writer
+	truncated bool
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if w.truncated {
+		if w.overflow != nil {
+			return w.overflow.Write(p)
+		}
+		return len(p), nil
+	}
+	remaining := w.max - w.buf.Len()
+	if len(p) <= remaining {
+		return w.buf.Write(p)
+	}
+	w.buf.Write(p[:remaining])
+	w.truncated = true
+	if w.overflow != nil {
+		if _, err := w.overflow.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		if _, err := w.overflow.Write(p[remaining:]); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -1,99 +1,319 @@
 package shell
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
+	"time"
 )
 
+const DefaultMaxOutput = 4 << 20
+
+// defaultCtx bounds commands run without an explicit context, so a caller
+// like cli.Run can time out everything a command starts.
+var defaultCtx = context.Background()
+var defaultCtxMu sync.RWMutex
+
+// WithContext calls fn with RunCmd and RunAttached using ctx, and restores
+// the previous context only after fn returns, so nothing fn started can
+// outlive ctx.
+func WithContext(ctx context.Context, fn func() error) error {
+	defaultCtxMu.Lock()
+	previous := defaultCtx
+	defaultCtx = ctx
+	defaultCtxMu.Unlock()
+	defer func() {
+		defaultCtxMu.Lock()
+		defaultCtx = previous
+		defaultCtxMu.Unlock()
+	}()
+	return fn()
+}
+
+// Context returns the context RunCmd uses, for callers that want to derive
+// a shorter deadline from it.
+func Context() context.Context {
+	defaultCtxMu.RLock()
+	defer defaultCtxMu.RUnlock()
+	return defaultCtx
+}
+
+// ExecCommand is swapped out by shelltest.Install so tests don't spawn real
+// processes.
+var ExecCommand = exec.CommandContext
+
 type Cmd struct {
-	dir *string
-	cmd []string
+	dir       *string
+	cmd       []string
+	env       []string
+	maxOutput int
+	truncated bool
+	combined  bool
+	quiet     bool
 }
 
 func New(cmd string, vars ...string) Cmd {
-	return Cmd{nil, createCmdArray(cmd, vars)}
+	return Cmd{dir: nil, cmd: createCmdArray(cmd, vars)}
 }
 
 func NewWithDir(dir, cmd string, vars ...string) Cmd {
-	return Cmd{&dir, createCmdArray(cmd, vars)}
+	return Cmd{dir: &dir, cmd: createCmdArray(cmd, vars)}
+}
+
+// NewShell runs script with `sh -c`, for the rare command that needs pipes,
+// `$(...)`, or globbing. script is interpreted by the shell, so never build it
+// from untrusted input; prefer New or NewFromArray, which don't use a shell.
+func NewShell(script string) Cmd {
+	return Cmd{dir: nil, cmd: []string{"sh", "-c", script}}
+}
+
+func NewShellWithDir(dir, script string) Cmd {
+	return Cmd{dir: &dir, cmd: []string{"sh", "-c", script}}
+}
+
+func NewFromArray(cmd []string) Cmd {
+	return Cmd{dir: nil, cmd: cmd}
+}
+
+func NewFromArrayWithDir(dir string, cmd []string) Cmd {
+	return Cmd{dir: &dir, cmd: cmd}
+}
+
+// WithEnv returns a copy of c that runs with env ("KEY=value" entries)
+// appended to the process environment. Later entries win on duplicate keys.
+func (c Cmd) WithEnv(env []string) Cmd {
+	c.env = append(append([]string{}, c.env...), env...)
+	return c
+}
+
+// WithArgs returns a copy of c with args appended verbatim.
+func (c Cmd) WithArgs(args ...string) Cmd {
+	c.cmd = append(append([]string{}, c.cmd...), args...)
+	return c
+}
+
+// WithCombinedOutput returns a copy of c whose stderr is captured along
+// with stdout, for tools like git fetch that report progress on stderr.
+func (c Cmd) WithCombinedOutput() Cmd {
+	c.combined = true
+	return c
+}
+
+// WithQuiet returns a copy of c whose RunCmd neither echoes the command nor
+// prints its stdout, for output that's only captured to be parsed.
+func (c Cmd) WithQuiet() Cmd {
+	c.quiet = true
+	return c
+}
+
+// WithMaxOutput caps how many bytes of stdout RunCmd keeps in memory.
+func (c Cmd) WithMaxOutput(n int) Cmd {
+	c.maxOutput = n
+	return c
+}
+
+// Truncated reports whether the last RunCmd produced more stdout than the
+// cap, in which case the returned output only holds the first part of it.
+func (c *Cmd) Truncated() bool {
+	return c.truncated
 }
 
 func createCmdArray(cmd string, vars []string) []string {
 	for _, curr := range vars {
 		cmd = strings.Replace(cmd, "%v", curr, 1)
 	}
-	return parseCommand(cmd)
+	return tokenize(cmd, true)
 }
 
-func parseCommand(cmd string) []string {
-	var result []string
-	var buffer bytes.Buffer
+// Split breaks a command line on spaces, treating double-quoted sections as
+// part of a single argument and dropping the quotes, for user-supplied
+// command templates. New keeps the quotes in the argument.
+func Split(cmd string) []string {
+	return tokenize(cmd, false)
+}
+
+func tokenize(cmd string, keepQuotes bool) []string {
+	args := []string{}
+	var current strings.Builder
 	inQuotes := false
+	hasArg := false
 	for _, c := range cmd {
-		switch c {
-		case ' ':
-			if inQuotes {
-				buffer.WriteRune(c)
-			} else if buffer.Len() > 0 {
-				result = append(result, buffer.String())
-				buffer.Reset()
-			}
-		case '"':
+		switch {
+		case c == '"':
 			inQuotes = !inQuotes
-			buffer.WriteRune(c)
+			hasArg = true
+			if keepQuotes {
+				current.WriteRune(c)
+			}
+		case c == ' ' && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
 		default:
-			buffer.WriteRune(c)
+			current.WriteRune(c)
+			hasArg = true
 		}
 	}
-	if buffer.Len() > 0 {
-		result = append(result, buffer.String())
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// CmdError is what RunCmd returns when a command fails, so callers can
+// look at its stderr. It unwraps to the underlying exec error.
+type CmdError struct {
+	Cmd    []string
+	Dir    string
+	Stderr string
+	Err    error
+}
+
+func (e *CmdError) Error() string {
+	return fmt.Sprintf("could not run command: %v\n in dir %v\n with error message: %v\n and stderr: %v", e.Cmd, e.Dir, e.Err, e.Stderr)
+}
+
+func (e *CmdError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode is the command's exit status, or -1 if it didn't exit normally.
+func (e *CmdError) ExitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
 	}
-	return result
+	return -1
 }
 
 func (c *Cmd) RunCmd() (string, error) {
-	if c.dir != nil {
-		fmt.Printf("dir: %v cmd: %s\n", *c.dir, strings.Join(c.cmd, " "))
-	} else {
-		fmt.Printf("cmd: %s\n", strings.Join(c.cmd, " "))
-	}
-	toRun := exec.Command(c.cmd[0], c.cmd[1:]...)
-	var stdout, stderr bytes.Buffer
-	toRun.Stdout = &stdout
-	toRun.Stderr = &stderr
+	return c.RunCmdContext(Context())
+}
+
+// RunCmdContext is RunCmd, but the process is killed when ctx is done.
+func (c *Cmd) RunCmdContext(ctx context.Context) (string, error) {
+	if !c.quiet {
+		if c.dir != nil {
+			fmt.Fprintf(os.Stdout, "dir: %v cmd: %s\n", *c.dir, strings.Join(c.cmd, " "))
+		} else {
+			fmt.Fprintf(os.Stdout, "cmd: %s\n", strings.Join(c.cmd, " "))
+		}
+	}
+	toRun := ExecCommand(ctx, c.cmd[0], c.cmd[1:]...)
+	maxOutput := c.maxOutput
+	if maxOutput <= 0 {
+		maxOutput = DefaultMaxOutput
+	}
+	out := &cappedWriter{max: maxOutput}
+	if !c.quiet {
+		out.overflow = os.Stdout
+	}
+	errOut := &cappedWriter{max: maxOutput}
+	toRun.Stdout = out
+	toRun.Stderr = errOut
+	if c.combined {
+		toRun.Stderr = out
+	}
 	if c.dir != nil {
 		toRun.Dir = *c.dir
 	}
+	if len(c.env) > 0 {
+		toRun.Env = append(os.Environ(), c.env...)
+	}
 	if err := toRun.Run(); err != nil {
-		var dir string
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("timed out running command: %v", c.cmd)
+		}
+		// Failing tools like test runners often explain why on stdout.
+		if printOut := out.buf.String(); printOut != "" && !c.quiet {
+			fmt.Fprintln(os.Stdout, printOut)
+		}
+		dir := "N/A"
 		if c.dir != nil {
 			dir = *c.dir
-		} else {
-			dir = "N/A"
 		}
-		return "", fmt.Errorf("could not run command: %v\n in dir %v\n with error message: %v\n and stderr: %v", c.cmd, dir, err, stderr.String())
+		return "", &CmdError{Cmd: c.cmd, Dir: dir, Stderr: errOut.buf.String(), Err: err}
+	}
+	c.truncated = out.truncated
+	printOut := out.buf.String()
+	if c.quiet {
+		return printOut, nil
+	}
+	// Tools like git push report what they did on stderr.
+	if printErr := errOut.buf.String(); printErr != "" {
+		fmt.Fprint(os.Stderr, printErr)
 	}
-	printOut := stdout.String()
-	if printOut != "" {
-		fmt.Println(printOut)
+	if c.truncated {
+		fmt.Fprintf(os.Stdout, "\noutput exceeded %v bytes and was truncated\n", maxOutput)
+	} else if printOut != "" {
+		fmt.Fprintln(os.Stdout, printOut)
 	}
 	return printOut, nil
 }
 
+// RunAttached runs c connected to the terminal, for interactive programs
+// like editors. Nothing is captured.
+func (c *Cmd) RunAttached() error {
+	return c.RunAttachedContext(Context())
+}
+
+func (c *Cmd) RunAttachedContext(ctx context.Context) error {
+	toRun := ExecCommand(ctx, c.cmd[0], c.cmd[1:]...)
+	toRun.Stdin = os.Stdin
+	toRun.Stdout = os.Stdout
+	toRun.Stderr = os.Stderr
+	if c.dir != nil {
+		toRun.Dir = *c.dir
+	}
+	if len(c.env) > 0 {
+		toRun.Env = append(os.Environ(), c.env...)
+	}
+	if err := toRun.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out running command: %v", c.cmd)
+		}
+		return fmt.Errorf("could not run command: %v\n with error message: %w", c.cmd, err)
+	}
+	return nil
+}
+
 func RunCmdsFromStr(dir string, cmds ...string) ([]string, error) {
 	result := []Cmd{}
 	for _, cmd := range cmds {
-		result = append(result, NewWithDir(dir, cmd))
+		result = append(result, New(cmd))
+	}
+	return RunCmdsInDir(dir, result)
+}
+
+// RunCmdsInDir is RunCmds with dir applied to every command that doesn't
+// already have one. Like RunCmds, each cmd's Truncated reflects its own run.
+func RunCmdsInDir(dir string, cmds []Cmd) ([]string, error) {
+	inDir := make([]Cmd, len(cmds))
+	for i, cmd := range cmds {
+		if cmd.dir == nil {
+			cmd.dir = &dir
+		}
+		inDir[i] = cmd
+	}
+	outs, err := RunCmds(inDir)
+	for i := range cmds {
+		cmds[i].truncated = inDir[i].truncated
 	}
-	return RunCmds(result)
+	return outs, err
 }
 
+// RunCmds runs cmds in order, stopping at the first failure. Each cmd's
+// Truncated reflects its own run.
 func RunCmds(cmds []Cmd) ([]string, error) {
 	outs := []string{}
-	for _, cmd := range cmds {
-		out, err := cmd.RunCmd()
+	for i := range cmds {
+		out, err := cmds[i].RunCmd()
 		if err != nil {
 			return nil, err
 		}
@@ -110,3 +330,115 @@ func PrintCmds(cmds [][]string) {
 		fmt.Println()
 	}
 }
+
+const DefaultParallel = 8
+
+// Result is the outcome of one command run by RunCmdsConcurrent. Index is
+// the command's position in the slice that was passed in, and Truncated
+// reports whether Out was cut off at the command's output cap.
+type Result struct {
+	Index     int
+	Out       string
+	Err       error
+	Truncated bool
+}
+
+func RunCmdsConcurrent(cmds []Cmd) []Result {
+	return RunCmdsConcurrentN(cmds, DefaultParallel)
+}
+
+// ForEachN calls fn with every index below count, running at most n calls
+// at a time, or all of them at once when n is 0. It returns once every call
+// has.
+func ForEachN(n int, count int, fn func(i int)) {
+	if n <= 0 || n > count {
+		n = count
+	}
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// RunCmdsConcurrentN runs at most n commands at a time, or all of them at
+// once when n is 0. Results are returned in the order of cmds.
+func RunCmdsConcurrentN(cmds []Cmd, n int) []Result {
+	results := make([]Result, len(cmds))
+	ForEachN(n, len(cmds), func(i int) {
+		out, err := cmds[i].RunCmd()
+		results[i] = Result{Index: i, Out: out, Err: err, Truncated: cmds[i].Truncated()}
+	})
+	return results
+}
+
+// Poll calls check every interval until it reports done, returns an error,
+// timeout passes, or the default context is done.
+func Poll(check func() (bool, error), interval time.Duration, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	ctx := Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+}
+
+// ErrSkipped is the Err of commands RunCmdsConcurrentFailFast never started.
+var ErrSkipped = fmt.Errorf("skipped after an earlier failure")
+
+// RunCmdsConcurrentFailFast is RunCmdsConcurrentN, except that after the
+// first failure no more commands are started and running ones are killed.
+func RunCmdsConcurrentFailFast(cmds []Cmd, n int) []Result {
+	ctx, cancel := context.WithCancel(Context())
+	defer cancel()
+	results := make([]Result, len(cmds))
+	ForEachN(n, len(cmds), func(i int) {
+		if ctx.Err() != nil {
+			results[i] = Result{Index: i, Err: ErrSkipped}
+			return
+		}
+		out, err := cmds[i].RunCmdContext(ctx)
+		if err != nil {
+			cancel()
+		}
+		results[i] = Result{Index: i, Out: out, Err: err, Truncated: cmds[i].Truncated()}
+	})
+	return results
+}
+
+// Failed combines the errors in results into one, or returns nil if every
+// command succeeded.
+func Failed(results []Result) error {
+	msgs := []string{}
+	for _, r := range results {
+		if r.Err != nil {
+			msgs = append(msgs, r.Err.Error())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%v of %v commands failed:\n%v", len(msgs), len(results), strings.Join(msgs, "\n"))
+}
@@ -0,0 +1,222 @@
+package shell_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"toolbelt/pkg/shell"
+	"toolbelt/pkg/shell/shelltest"
+)
+
+func TestHelperProcess(t *testing.T) {
+	shelltest.HelperProcess()
+}
+
+func TestRunCmd(t *testing.T) {
+	fake := shelltest.Install(t)
+	c := shell.New("echo hello world").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "hello world" {
+		t.Errorf("got %q, want %q", out, "hello world")
+	}
+	want := [][]string{{"echo", "hello", "world"}}
+	if got := fake.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got calls %v, want %v", got, want)
+	}
+}
+
+func TestRunCmdError(t *testing.T) {
+	shelltest.Install(t)
+	c := shell.New("fail boom").WithQuiet()
+	_, err := c.RunCmd()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("error %q doesn't include stderr", err)
+	}
+}
+
+func TestRunCmdsStopsAtFirstError(t *testing.T) {
+	fake := shelltest.Install(t)
+	cmds := []shell.Cmd{
+		shell.New("echo a").WithQuiet(),
+		shell.New("fail b").WithQuiet(),
+		shell.New("echo c").WithQuiet(),
+	}
+	_, err := shell.RunCmds(cmds)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	want := [][]string{{"echo", "a"}, {"fail", "b"}}
+	if got := fake.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got calls %v, want %v", got, want)
+	}
+}
+
+func TestRunCmdsOrder(t *testing.T) {
+	shelltest.Install(t)
+	cmds := []shell.Cmd{
+		shell.New("echo a").WithQuiet(),
+		shell.New("echo b").WithQuiet(),
+		shell.New("echo c").WithQuiet(),
+	}
+	outs, err := shell.RunCmds(cmds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(outs, want) {
+		t.Errorf("got %v, want %v", outs, want)
+	}
+}
+
+func TestRunCmdsConcurrentKeepsOrder(t *testing.T) {
+	shelltest.Install(t)
+	// later commands finish first
+	cmds := []shell.Cmd{
+		shell.New("sleep 300ms first").WithQuiet(),
+		shell.New("sleep 150ms second").WithQuiet(),
+		shell.New("sleep 0s third").WithQuiet(),
+	}
+	results := shell.RunCmdsConcurrent(cmds)
+	want := []string{"first", "second", "third"}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("result %v has index %v", i, r.Index)
+		}
+		if r.Err != nil {
+			t.Errorf("result %v failed: %v", i, r.Err)
+		}
+		if r.Out != want[i] {
+			t.Errorf("result %v got %q, want %q", i, r.Out, want[i])
+		}
+	}
+}
+
+func TestFailedAggregatesErrors(t *testing.T) {
+	shelltest.Install(t)
+	cmds := []shell.Cmd{
+		shell.New("fail one").WithQuiet(),
+		shell.New("echo ok").WithQuiet(),
+		shell.New("fail two").WithQuiet(),
+	}
+	err := shell.Failed(shell.RunCmdsConcurrentN(cmds, 2))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "2 of 3 commands failed") {
+		t.Errorf("got %q", msg)
+	}
+	if !strings.Contains(msg, "one") || !strings.Contains(msg, "two") {
+		t.Errorf("%q is missing a failure", msg)
+	}
+}
+
+func TestFailedNil(t *testing.T) {
+	if err := shell.Failed([]shell.Result{{Index: 0}, {Index: 1}}); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestMaxOutput(t *testing.T) {
+	shelltest.Install(t)
+	c := shell.New("big 100").WithQuiet().WithMaxOutput(10)
+	out, err := c.RunCmd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 10 {
+		t.Errorf("got %v bytes, want 10", len(out))
+	}
+	if !c.Truncated() {
+		t.Error("expected Truncated")
+	}
+
+	c = shell.New("big 10").WithQuiet().WithMaxOutput(10)
+	_, err = c.RunCmd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Truncated() {
+		t.Error("output at the cap shouldn't be truncated")
+	}
+}
+
+func TestResultTruncated(t *testing.T) {
+	shelltest.Install(t)
+	cmds := []shell.Cmd{
+		shell.New("big 100").WithQuiet().WithMaxOutput(10),
+		shell.New("big 5").WithQuiet().WithMaxOutput(10),
+	}
+	results := shell.RunCmdsConcurrent(cmds)
+	if !results[0].Truncated {
+		t.Error("expected the first result to be truncated")
+	}
+	if results[1].Truncated {
+		t.Error("didn't expect the second result to be truncated")
+	}
+}
+
+func TestRunCmdsTruncated(t *testing.T) {
+	shelltest.Install(t)
+	for _, run := range []func([]shell.Cmd) ([]string, error){
+		shell.RunCmds,
+		func(cmds []shell.Cmd) ([]string, error) { return shell.RunCmdsInDir(t.TempDir(), cmds) },
+	} {
+		cmds := []shell.Cmd{
+			shell.New("big 100").WithQuiet().WithMaxOutput(10),
+			shell.New("big 5").WithQuiet().WithMaxOutput(10),
+		}
+		_, err := run(cmds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmds[0].Truncated() || cmds[1].Truncated() {
+			t.Errorf("got Truncated %v and %v, want true and false", cmds[0].Truncated(), cmds[1].Truncated())
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"git status", []string{"git", "status"}},
+		{"  git   status ", []string{"git", "status"}},
+		{`open -a "Google Chrome" %v`, []string{"open", "-a", "Google Chrome", "%v"}},
+		{`echo ""`, []string{"echo", ""}},
+	}
+	for _, tt := range tests {
+		if got := shell.Split(tt.cmd); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestNewKeepsQuotes(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		vars []string
+		want []string
+	}{
+		{"git status", nil, []string{"git", "status"}},
+		{`echo "a b" c`, nil, []string{"echo", `"a b"`, "c"}},
+		{"git commit -m %v", []string{`"fix it"`}, []string{"git", "commit", "-m", `"fix it"`}},
+		{`echo ""`, nil, []string{"echo", `""`}},
+	}
+	for _, tt := range tests {
+		fake := shelltest.Install(t)
+		c := shell.New(tt.cmd, tt.vars...).WithQuiet()
+		if _, err := c.RunCmd(); err != nil {
+			t.Fatal(err)
+		}
+		if got := fake.Calls(); len(got) != 1 || !reflect.DeepEqual(got[0], tt.want) {
+			t.Errorf("New(%q) ran %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
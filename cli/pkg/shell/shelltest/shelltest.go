@@ -0,0 +1,115 @@
+// Package shelltest fakes the processes pkg/shell runs, using the standard
+// helper process pattern: the test binary re-runs itself as the "program".
+//
+// A test package using it needs
+//
+//	func TestHelperProcess(t *testing.T) {
+//		shelltest.HelperProcess()
+//	}
+package shelltest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"toolbelt/pkg/shell"
+)
+
+const envVar = "GO_WANT_HELPER_PROCESS"
+const stdoutVar = "SHELLTEST_STDOUT"
+const stderrVar = "SHELLTEST_STDERR"
+const exitVar = "SHELLTEST_EXIT"
+
+// Fake records every command run while it's installed.
+type Fake struct {
+	t     *testing.T
+	mu    sync.Mutex
+	calls [][]string
+}
+
+// Stub makes programs HelperProcess doesn't know print stdout and stderr
+// and exit with code, e.g. to fake lsof.
+func (f *Fake) Stub(stdout string, stderr string, code int) {
+	f.t.Setenv(stdoutVar, stdout)
+	f.t.Setenv(stderrVar, stderr)
+	f.t.Setenv(exitVar, strconv.Itoa(code))
+}
+
+// Install routes shell's commands to HelperProcess until t finishes.
+func Install(t *testing.T) *Fake {
+	f := &Fake{t: t}
+	previous := shell.ExecCommand
+	shell.ExecCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		argv := append([]string{name}, args...)
+		f.mu.Lock()
+		f.calls = append(f.calls, argv)
+		f.mu.Unlock()
+		helperArgs := append([]string{"-test.run=TestHelperProcess", "--"}, argv...)
+		return exec.CommandContext(ctx, os.Args[0], helperArgs...)
+	}
+	t.Setenv(envVar, "1")
+	t.Cleanup(func() {
+		shell.ExecCommand = previous
+	})
+	return f
+}
+
+// Calls returns the argv of every command run so far, in the order they
+// started.
+func (f *Fake) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string{}, f.calls...)
+}
+
+// HelperProcess stands in for the faked program when the test binary is
+// re-run by Install, and does nothing otherwise. It understands:
+//
+//	echo <args...>        prints args joined by spaces
+//	fail [message]        prints message to stderr and exits 1
+//	sleep <dur> <output>  waits, then prints output
+//	big <n>               prints n bytes
+//
+// Anything else prints what Stub set and exits with its code, succeeding
+// silently by default.
+func HelperProcess() {
+	if os.Getenv(envVar) != "1" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	if len(args) == 0 {
+		os.Exit(2)
+	}
+	switch args[0] {
+	case "echo":
+		fmt.Print(strings.Join(args[1:], " "))
+	case "fail":
+		fmt.Fprint(os.Stderr, strings.Join(args[1:], " "))
+		os.Exit(1)
+	case "sleep":
+		d, _ := time.ParseDuration(args[1])
+		time.Sleep(d)
+		fmt.Print(args[2])
+	case "big":
+		n, _ := strconv.Atoi(args[1])
+		fmt.Print(strings.Repeat("x", n))
+	default:
+		fmt.Print(os.Getenv(stdoutVar))
+		fmt.Fprint(os.Stderr, os.Getenv(stderrVar))
+		code, _ := strconv.Atoi(os.Getenv(exitVar))
+		os.Exit(code)
+	}
+	os.Exit(0)
+}
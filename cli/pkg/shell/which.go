@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// installHints say how to install the tools toolbelt shells out to.
+var installHints = map[string]string{
+	"git":      "brew install git",
+	"go":       "brew install go",
+	"gh":       "brew install gh",
+	"aws":      "brew install awscli",
+	"kubectl":  "brew install kubectl",
+	"devspace": "brew install devspace",
+	"code":     "the VS Code command palette: Shell Command: Install 'code' command in PATH",
+	"lsof":     "your system package manager",
+	"delta":    "brew install git-delta",
+	"brew":     "the script at https://brew.sh",
+	"poetry":   "brew install poetry",
+}
+
+// InstallHint says how to install tool, or "" if it's not a known tool.
+func InstallHint(tool string) string {
+	return installHints[tool]
+}
+
+// Which returns the path of tool if it's on the PATH.
+func Which(tool string) (string, bool) {
+	p, err := exec.LookPath(tool)
+	return p, err == nil
+}
+
+// MissingError is returned by Require. It matches exec.ErrNotFound with
+// errors.Is.
+type MissingError struct {
+	Tool string
+}
+
+func (e MissingError) Error() string {
+	if hint := InstallHint(e.Tool); hint != "" {
+		return fmt.Sprintf("%v is required for this command; install it with %v", e.Tool, hint)
+	}
+	return fmt.Sprintf("%v is required for this command; install it and make sure it's on your PATH", e.Tool)
+}
+
+func (e MissingError) Unwrap() error {
+	return exec.ErrNotFound
+}
+
+// Require checks that every tool is on the PATH, so a command can fail up
+// front with an actionable message instead of partway through.
+func Require(tools ...string) error {
+	for _, tool := range tools {
+		if _, ok := Which(tool); !ok {
+			return MissingError{Tool: tool}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type Table struct {
+	headers []string
+	rows    [][]string
+	color   bool
+}
+
+func New(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// WithColor renders the header row in bold unless NO_COLOR is set.
+func (t *Table) WithColor() *Table {
+	t.color = os.Getenv("NO_COLOR") == ""
+	return t
+}
+
+func (t *Table) AddRow(cells ...interface{}) {
+	row := []string{}
+	for _, cell := range cells {
+		row = append(row, fmt.Sprint(cell))
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *Table) Render(w io.Writer) error {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	if len(t.headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+	out := buf.String()
+	// color after aligning so the escape codes don't count toward widths
+	if t.color && len(t.headers) > 0 {
+		header, rest, _ := strings.Cut(out, "\n")
+		out = "\x1b[1m" + header + "\x1b[0m\n" + rest
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
@@ -0,0 +1,19 @@
+package tty
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Plain turns off every prompt, so commands take their non-interactive path
+// even in a terminal, e.g. when run from a script or launchd job.
+var Plain = false
+
+// stdout is captured before main can swap os.Stdout for the --output tee,
+// so the check is against the real terminal.
+var stdout = os.Stdout
+
+func IsInteractive() bool {
+	return !Plain && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(stdout.Fd()))
+}
@@ -0,0 +1,70 @@
+package vscode
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"toolbelt/internal/config"
+	"toolbelt/pkg/comparable"
+	"toolbelt/pkg/shell"
+)
+
+func SettingsPath() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		return path.Join(home, "Library/Application Support/Code/User/settings.json")
+	case "windows":
+		return path.Join(os.Getenv("APPDATA"), "Code/User/settings.json")
+	default:
+		return path.Join(home, ".config/Code/User/settings.json")
+	}
+}
+
+func installedExtensions() ([]string, error) {
+	if err := shell.Require("code"); err != nil {
+		return nil, err
+	}
+	c := shell.New("code --list-extensions").WithQuiet()
+	out, err := c.RunCmd()
+	if err != nil {
+		return nil, err
+	}
+	return lines(out), nil
+}
+
+func lines(s string) []string {
+	result := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func PullExtensions() error {
+	bytes, err := os.ReadFile(config.VSCODE_DOTFILES_EXTENSIONS)
+	if err != nil {
+		return err
+	}
+	installed, err := installedExtensions()
+	if err != nil {
+		return err
+	}
+	cmds := []shell.Cmd{}
+	for _, extension := range comparable.Subtract(lines(string(bytes)), installed) {
+		cmds = append(cmds, shell.New("code --install-extension %v", extension))
+	}
+	_, err = shell.RunCmds(cmds)
+	return err
+}
+
+func PushExtensions() error {
+	installed, err := installedExtensions()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(config.VSCODE_DOTFILES_EXTENSIONS, []byte(strings.Join(installed, "\n")+"\n"), 0644)
+}